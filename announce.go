@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const (
+	// defaultAnnounceSelector 为公告详情页正文容器的默认选择器。
+	defaultAnnounceSelector = ".news_detail, .news_con, .article"
+	// defaultSummaryLength 为推送中正文摘要的默认最大字数。
+	defaultSummaryLength = 120
+	// defaultTrackLatest 为默认跟踪正文修改的最新公告条数。
+	defaultTrackLatest = 5
+	// maxDiffLines 为推送中展示的修改段落行数上限。
+	maxDiffLines = 10
+)
+
+// AnnounceDetailConfig 控制检测到新公告后读取详情页正文：
+// Selector 为正文容器的 CSS 选择器，SummaryLength 为推送中摘要的最大字数，0 表示默认值。
+// TrackEdits 开启后每次检查都会读取最新 TrackLatest 条公告的正文，正文有修改时推送“公告已更新”。
+type AnnounceDetailConfig struct {
+	Selector      string `json:"selector"`
+	SummaryLength int    `json:"summaryLength"`
+	TrackEdits    bool   `json:"trackEdits"`
+	TrackLatest   int    `json:"trackLatest"`
+}
+
+func (c AnnounceDetailConfig) withDefaults() AnnounceDetailConfig {
+	if strings.TrimSpace(c.Selector) == "" {
+		c.Selector = defaultAnnounceSelector
+	}
+	if c.SummaryLength <= 0 {
+		c.SummaryLength = defaultSummaryLength
+	}
+	if c.TrackLatest <= 0 {
+		c.TrackLatest = defaultTrackLatest
+	}
+	return c
+}
+
+func validateAnnounceDetail(c AnnounceDetailConfig) error {
+	if c.SummaryLength < 0 {
+		return errors.New("公告摘要字数不能为负数")
+	}
+	if c.TrackLatest < 0 || c.TrackLatest > 20 {
+		return errors.New("跟踪修改的公告条数应为 0-20")
+	}
+	if err := validateSelector("公告正文选择器", c.Selector); err != nil {
+		return err
+	}
+	return nil
+}
+
+// fetchArticleParagraphs 读取详情页，返回正文容器中去掉空行后的各段文字。
+func fetchArticleParagraphs(ctx context.Context, client *http.Client, link string, selector string) ([]string, error) {
+	doc, err := fetchDocument(ctx, client, link)
+	if err != nil {
+		return nil, err
+	}
+	content := doc.Find(selector).First()
+	if content.Length() == 0 {
+		return nil, errors.New("未找到正文: " + selector)
+	}
+	return articleParagraphs(content), nil
+}
+
+// articleParagraphs 按换行与块级元素拆分正文，合并段内多余空白。
+func articleParagraphs(sel *goquery.Selection) []string {
+	sel.Find("script, style").Remove()
+	sel.Find("br").ReplaceWithHtml("\n")
+	sel.Find("p, div, li, tr, h1, h2, h3, h4, h5, h6").AppendHtml("\n")
+
+	var out []string
+	for _, line := range strings.Split(sel.Text(), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// summarizeText 把正文压成一行，超过 limit 个字时截断并加省略号。
+func summarizeText(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	return string([]rune(text)[:limit]) + "…"
+}
+
+// attachAnnounceBody 读取公告详情页正文，写入条目的全文与摘要；失败时只记录日志。
+func (m *Monitor) attachAnnounceBody(ctx context.Context, appCtx context.Context, run checkRun, item *latestItem) {
+	if item.Body != "" || strings.TrimSpace(item.Link) == "" {
+		return
+	}
+	paragraphs, err := fetchArticleParagraphs(ctx, m.httpClient, item.Link, run.announce.Selector)
+	if err != nil {
+		m.emitLog(appCtx, "WARN", "读取公告正文失败: "+err.Error())
+		return
+	}
+	item.Body = strings.Join(paragraphs, "\n")
+	item.Summary = summarizeText(item.Body, run.announce.SummaryLength)
+}
+
+// announceContent 为已跟踪公告正文的内容指纹与分段，用于发现修改并生成差异。
+type announceContent struct {
+	Hash       string   `json:"hash"`
+	Paragraphs []string `json:"paragraphs"`
+}
+
+// announceEditSource 为已见公告被修改时的推送类别，推送内容为修改后的段落。
+type announceEditSource struct{}
+
+func (announceEditSource) Name() string     { return "公告更新" }
+func (announceEditSource) PushHead() string { return "公告已更新" }
+
+func hashParagraphs(paragraphs []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(paragraphs, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+func copyAnnounceContents(in map[string]announceContent) map[string]announceContent {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]announceContent, len(in))
+	for key, c := range in {
+		c.Paragraphs = append([]string(nil), c.Paragraphs...)
+		out[key] = c
+	}
+	return out
+}
+
+// diffParagraphs 按最长公共子序列比较新旧段落，返回以 "- " 表示删除、"+ " 表示新增的差异行。
+func diffParagraphs(old []string, cur []string) []string {
+	lcs := make([][]int, len(old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(cur)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(cur) - 1; j >= 0; j-- {
+			if old[i] == cur[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(old) && j < len(cur) {
+		switch {
+		case old[i] == cur[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "- "+old[i])
+			i++
+		default:
+			out = append(out, "+ "+cur[j])
+			j++
+		}
+	}
+	for ; i < len(old); i++ {
+		out = append(out, "- "+old[i])
+	}
+	for ; j < len(cur); j++ {
+		out = append(out, "+ "+cur[j])
+	}
+	return out
+}
+
+// formatDiff 把差异行截断为推送中展示的文字：每行最多 limit 字，最多 maxDiffLines 行。
+func formatDiff(lines []string, limit int) string {
+	var b strings.Builder
+	for i, line := range lines {
+		if i == maxDiffLines {
+			b.WriteString("\n……共 " + strconv.Itoa(len(lines)) + " 处修改")
+			break
+		}
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(summarizeText(line, limit))
+	}
+	return b.String()
+}
+
+// checkAnnounceEdits 读取列表中最新 N 条公告的正文，与上次的内容指纹比较，发现修改时推送差异，并重新解析维护时间。
+// 首次跟踪的公告只记录指纹；读取到的正文写回 all，后续处理新公告时不再重复请求。
+func (m *Monitor) checkAnnounceEdits(ctx context.Context, appCtx context.Context, run checkRun, all []latestItem) {
+	if !run.announce.TrackEdits {
+		return
+	}
+	n := min(run.announce.TrackLatest, len(all))
+
+	m.mu.Lock()
+	prev := copyAnnounceContents(m.annContents)
+	m.mu.Unlock()
+
+	next := map[string]announceContent{}
+	var edited []latestItem
+	for i := 0; i < n; i++ {
+		it := &all[i]
+		old, tracked := prev[it.Key]
+		paragraphs, err := fetchArticleParagraphs(ctx, m.httpClient, it.Link, run.announce.Selector)
+		if err != nil {
+			m.emitLog(appCtx, "WARN", "读取公告正文失败: "+err.Error())
+			if tracked {
+				next[it.Key] = old
+			}
+			continue
+		}
+		it.Body = strings.Join(paragraphs, "\n")
+		it.Summary = summarizeText(it.Body, run.announce.SummaryLength)
+
+		cur := announceContent{Hash: hashParagraphs(paragraphs), Paragraphs: paragraphs}
+		next[it.Key] = cur
+		if tracked && old.Hash != cur.Hash {
+			e := *it
+			e.Summary = formatDiff(diffParagraphs(old.Paragraphs, cur.Paragraphs), run.announce.SummaryLength)
+			edited = append(edited, e)
+		}
+	}
+
+	m.mu.Lock()
+	m.annContents = next
+	m.mu.Unlock()
+	m.persistSnapshot()
+
+	for _, it := range edited {
+		m.emitLog(appCtx, "INFO", "检测到公告修改: "+it.Title)
+		// 维护公告常在修改中延长时间，重新解析以更新维护提醒。
+		m.recordMaintenance(appCtx, it, run.now)
+		m.deliver(ctx, appCtx, run, announceEditSource{}, it)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestValidateAnnounceDetailSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		ok       bool
+	}{
+		{"", true},
+		{".news_detail .text", true},
+		{"div[[", false},
+		{"#content >", false},
+	}
+	for _, tt := range tests {
+		err := validateAnnounceDetail(AnnounceDetailConfig{Selector: tt.selector})
+		if (err == nil) != tt.ok {
+			t.Errorf("selector %q: err = %v", tt.selector, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), tt.selector) {
+			t.Errorf("错误中应包含选择器: %v", err)
+		}
+	}
+}
+
+func TestFetchArticleParagraphs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><div class="nav">首页</div><div class="text">
+			<p>亲爱的玩家：</p>
+			<p>我们将于  10月16日 8:00-11:00<br>进行停服维护。</p>
+			<script>var x = 1;</script>
+			<p>   </p>
+			<ul><li>修复若干问题</li><li>新增活动</li></ul>
+		</div></body></html>`))
+	}))
+	defer srv.Close()
+
+	got, err := fetchArticleParagraphs(context.Background(), srv.Client(), srv.URL, ".text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"亲爱的玩家：", "我们将于 10月16日 8:00-11:00", "进行停服维护。", "修复若干问题", "新增活动"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("段落 = %q, 期望 %q", got, want)
+	}
+
+	if _, err := fetchArticleParagraphs(context.Background(), srv.Client(), srv.URL, ".missing"); err == nil {
+		t.Fatal("找不到正文容器时应报错")
+	}
+}
+
+func TestSummarizeText(t *testing.T) {
+	tests := []struct {
+		text  string
+		limit int
+		want  string
+	}{
+		{"维护公告\n  今日停服", 0, "维护公告 今日停服"},
+		{"维护公告", 4, "维护公告"},
+		{"维护公告今日停服", 4, "维护公告…"},
+	}
+	for _, tt := range tests {
+		if got := summarizeText(tt.text, tt.limit); got != tt.want {
+			t.Errorf("summarizeText(%q, %d) = %q, 期望 %q", tt.text, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestDiffParagraphs(t *testing.T) {
+	tests := []struct {
+		old, cur []string
+		want     []string
+	}{
+		{[]string{"甲", "乙"}, []string{"甲", "乙"}, nil},
+		{[]string{"甲", "乙", "丙"}, []string{"甲", "丁", "丙"}, []string{"- 乙", "+ 丁"}},
+		{[]string{"甲"}, []string{"甲", "乙"}, []string{"+ 乙"}},
+		{[]string{"甲", "乙"}, []string{"乙"}, []string{"- 甲"}},
+		{nil, []string{"甲"}, []string{"+ 甲"}},
+	}
+	for _, tt := range tests {
+		if got := diffParagraphs(tt.old, tt.cur); !slices.Equal(got, tt.want) {
+			t.Errorf("diffParagraphs(%q, %q) = %q, 期望 %q", tt.old, tt.cur, got, tt.want)
+		}
+	}
+
+	lines := make([]string, maxDiffLines+2)
+	for i := range lines {
+		lines[i] = "+ 段落"
+	}
+	if got := formatDiff(lines, 120); !strings.HasSuffix(got, "\n……共 12 处修改") || strings.Count(got, "+ 段落") != maxDiffLines {
+		t.Errorf("formatDiff 超出行数上限 = %q", got)
+	}
+}
+
+func TestCheckAnnounceEdits(t *testing.T) {
+	useTempConfigDir(t)
+
+	var mu sync.Mutex
+	bodies := map[string]string{
+		"/a1": "<p>维护时间：10月16日 8:00-11:00</p><p>维护内容：例行维护</p>",
+		"/a2": "<p>国庆活动开启</p>",
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		body, ok := bodies[r.URL.Path]
+		mu.Unlock()
+		if !ok {
+			http.Error(w, "busy", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`<div class="article">` + body + `</div>`))
+	}))
+	defer srv.Close()
+	setBody := func(path, body string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if body == "" {
+			delete(bodies, path)
+		} else {
+			bodies[path] = body
+		}
+	}
+
+	m := NewMonitor()
+	m.httpClient = srv.Client()
+	rec := &recordNotifier{name: "记录"}
+	m.notifiers = []Notifier{rec}
+	m.announceDetail = AnnounceDetailConfig{TrackEdits: true, TrackLatest: 2}
+
+	check := func() []latestItem {
+		all := []latestItem{
+			{Key: "a2", Title: "国庆活动", Link: srv.URL + "/a2"},
+			{Key: "a1", Title: "维护公告", Link: srv.URL + "/a1"},
+		}
+		m.mu.Lock()
+		run := m.newCheckRunLocked(time.Now())
+		m.mu.Unlock()
+		m.checkAnnounceEdits(context.Background(), nil, run, all)
+		return all
+	}
+
+	// 首次跟踪只记录指纹，读取到的正文写回列表。
+	all := check()
+	if rec.count() != 0 || all[1].Body != "维护时间：10月16日 8:00-11:00\n维护内容：例行维护" {
+		t.Fatalf("首次检查: sent=%d body=%q", rec.count(), all[1].Body)
+	}
+
+	// 读取失败时保留原指纹，恢复后内容未变不算修改。
+	setBody("/a1", "")
+	check()
+	setBody("/a1", "<p>维护时间：10月16日 8:00-11:00</p><p>维护内容：例行维护</p>")
+	check()
+	if rec.count() != 0 {
+		t.Fatalf("正文未修改却推送了 %d 条", rec.count())
+	}
+
+	setBody("/a1", "<p>维护时间：10月16日 8:00-13:30</p><p>维护内容：例行维护</p>")
+	check()
+	check()
+	if rec.count() != 1 {
+		t.Fatalf("修改后推送 %d 条, 期望 1 条", rec.count())
+	}
+	msg := rec.sent[0]
+	if msg.Source != "公告更新" || msg.Title != "维护公告" || msg.Summary != "- 维护时间：10月16日 8:00-11:00\n+ 维护时间：10月16日 8:00-13:30" {
+		t.Errorf("修改推送 = %+v", msg)
+	}
+	if events := m.MaintenanceEvents(); len(events) != 1 || events[0].End.In(beijingTime).Format("15:04") != "13:30" {
+		t.Errorf("修改后未重新解析维护时间: %+v", events)
+	}
+}
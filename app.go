@@ -64,6 +64,15 @@ func (a *App) StopMonitoring() {
 	a.monitor.Stop()
 }
 
+// StartBurstCheck 开启加速检查：接下来 minutes 分钟内每 intervalSec 秒检查一次所有来源。
+func (a *App) StartBurstCheck(intervalSec int, minutes int) error {
+	return a.monitor.StartBurst(intervalSec, minutes)
+}
+
+func (a *App) StopBurstCheck() {
+	a.monitor.StopBurst()
+}
+
 func (a *App) GetStatus() MonitorStatus {
 	return a.monitor.Status()
 }
@@ -72,6 +81,44 @@ func (a *App) GetSettings() AppSettings {
 	return a.monitor.GetSettings()
 }
 
+func (a *App) SaveSettings(s AppSettings) error {
+	return a.monitor.SaveSettings(s)
+}
+
+// SendTestNotification 由前端“发送测试”按钮调用，使用示例消息测试单个推送渠道。
+func (a *App) SendTestNotification(cfg NotifierConfig) error {
+	return a.monitor.SendTest(context.Background(), cfg)
+}
+
+// RequeueDeadLetters 把超过重试上限的推送重新放回重试队列。
+func (a *App) RequeueDeadLetters() int {
+	return a.monitor.RequeueDeadLetters()
+}
+
+func (a *App) ClearDeadLetters() {
+	a.monitor.ClearDeadLetters()
+}
+
+// DryRunRules 试运行规则，返回示例条目会命中的规则与动作。
+func (a *App) DryRunRules(sample RuleSample) (RuleDryRun, error) {
+	return a.monitor.DryRunRules(sample)
+}
+
+// GetHistory 返回检测到的新条目历史，包括未提醒的条目，最新的在前。
+func (a *App) GetHistory() []HistoryEntry {
+	return a.monitor.History()
+}
+
+// GetMaintenanceEvents 返回从公告中解析出的维护时间段。
+func (a *App) GetMaintenanceEvents() []MaintenanceEvent {
+	return a.monitor.MaintenanceEvents()
+}
+
+// ExportMaintenanceCalendar 生成维护日历 .ics 文件，返回文件路径。
+func (a *App) ExportMaintenanceCalendar() (string, error) {
+	return a.monitor.ExportMaintenanceICS()
+}
+
 func (a *App) GetAppInfo() AppInfo {
 	return AppInfo{Name: AppName, Author: AppAuthor, Version: AppVersion}
 }
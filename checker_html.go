@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// HTMLSourceConfig 描述一个按 CSS 选择器抓取的网页来源，无需改代码即可新增监控页面。
+// ItemSelector 选出列表中的每一项；TitleSelector/LinkSelector 在每一项内查找，
+// 留空时分别取该项本身的文本与该项（或其中第一个 a）的 href。
+// KeyAttr 可选，指定用作去重 key 的属性（如 data-id），留空时以链接为 key。
+type HTMLSourceConfig struct {
+	Name          string `json:"name"`
+	Enabled       bool   `json:"enabled"`
+	URL           string `json:"url"`
+	ItemSelector  string `json:"itemSelector"`
+	TitleSelector string `json:"titleSelector"`
+	LinkSelector  string `json:"linkSelector"`
+	KeyAttr       string `json:"keyAttr"`
+	PushHead      string `json:"pushHead"`
+}
+
+type htmlSourceChecker struct {
+	cfg HTMLSourceConfig
+}
+
+func (c htmlSourceChecker) Name() string { return strings.TrimSpace(c.cfg.Name) }
+
+func (c htmlSourceChecker) PushHead() string {
+	if head := strings.TrimSpace(c.cfg.PushHead); head != "" {
+		return head
+	}
+	return c.Name() + "有更新了"
+}
+
+// fetchPage 以 GET 请求页面并返回响应体，非 2xx 时返回错误。
+func fetchPage(ctx context.Context, client *http.Client, pageURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, errors.New("HTTP " + resp.Status + ": " + strings.TrimSpace(string(b)))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// resolveLink 把 href 解析为基于 base 的绝对地址。
+func resolveLink(base *url.URL, href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return ""
+	}
+	if base == nil {
+		return href
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(ref).String()
+}
+
+func (c htmlSourceChecker) FetchLatest(ctx context.Context, client *http.Client) (latestItem, error) {
+	all, err := c.FetchAll(ctx, client)
+	if err != nil || len(all) == 0 {
+		return latestItem{}, err
+	}
+	return all[0], nil
+}
+
+func (c htmlSourceChecker) FetchAll(ctx context.Context, client *http.Client) ([]latestItem, error) {
+	pageURL := strings.TrimSpace(c.cfg.URL)
+	body, err := fetchPage(ctx, client, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	base, _ := url.Parse(pageURL)
+	titleSel := strings.TrimSpace(c.cfg.TitleSelector)
+	linkSel := strings.TrimSpace(c.cfg.LinkSelector)
+	keyAttr := strings.TrimSpace(c.cfg.KeyAttr)
+
+	var out []latestItem
+	seen := map[string]struct{}{}
+	doc.Find(strings.TrimSpace(c.cfg.ItemSelector)).Each(func(_ int, row *goquery.Selection) {
+		titleNode := row
+		if titleSel != "" {
+			titleNode = row.Find(titleSel).First()
+		}
+		title := strings.Join(strings.Fields(titleNode.Text()), " ")
+
+		linkNode := row
+		if linkSel != "" {
+			linkNode = row.Find(linkSel).First()
+		} else if !row.Is("a") {
+			linkNode = row.Find("a[href]").First()
+		}
+		href, _ := linkNode.Attr("href")
+		link := resolveLink(base, href)
+
+		key := ""
+		if keyAttr != "" {
+			if v, ok := row.Attr(keyAttr); ok {
+				key = strings.TrimSpace(v)
+			} else if v, ok := linkNode.Attr(keyAttr); ok {
+				key = strings.TrimSpace(v)
+			}
+		}
+		if key == "" {
+			key = link
+		}
+		if key == "" {
+			key = title
+		}
+		if key == "" || title == "" {
+			return
+		}
+		if _, dup := seen[key]; dup {
+			return
+		}
+		seen[key] = struct{}{}
+		out = append(out, latestItem{Key: key, Title: title, Link: link})
+	})
+	return out, nil
+}
+
+// validateSourceURL 校验来源地址为 http(s) 绝对地址。
+func validateSourceURL(raw string) error {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("地址需以 http:// 或 https:// 开头: " + raw)
+	}
+	return nil
+}
+
+// validateSourceNames 确保自定义来源名称非空且互不重复，也不与内置来源及推送类别重名。
+// 来源名称同时用作已见状态、推送模板与过滤规则的索引。
+func validateSourceNames(names []string) error {
+	used := map[string]bool{}
+	for _, c := range builtinCheckers() {
+		// 论坛版块可配置，其名称随 names 一起校验。
+		if _, ok := c.(forumChecker); ok {
+			continue
+		}
+		used[c.Name()] = true
+	}
+	for _, c := range pushCategories() {
+		used[c.Name()] = true
+	}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return errors.New("来源名称不能为空")
+		}
+		if used[name] {
+			return errors.New("来源名称重复: " + name)
+		}
+		used[name] = true
+	}
+	return nil
+}
+
+func validateHTMLSources(sources []HTMLSourceConfig) error {
+	for _, s := range sources {
+		if err := validateSourceURL(s.URL); err != nil {
+			return errors.New(s.Name + ": " + err.Error())
+		}
+		if strings.TrimSpace(s.ItemSelector) == "" {
+			return errors.New(s.Name + ": 列表项选择器不能为空")
+		}
+		selectors := []struct{ label, sel string }{
+			{"列表项选择器", s.ItemSelector},
+			{"标题选择器", s.TitleSelector},
+			{"链接选择器", s.LinkSelector},
+		}
+		for _, c := range selectors {
+			if err := validateSelector(c.label, c.sel); err != nil {
+				return errors.New(s.Name + ": " + err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// validateSelector 检查非空的 CSS 选择器能否解析。goquery 遇到无效选择器时只会匹配不到元素，
+// 不会报错，因此要在保存设置时提前发现。
+func validateSelector(label string, sel string) error {
+	sel = strings.TrimSpace(sel)
+	if sel == "" {
+		return nil
+	}
+	if _, err := cascadia.Compile(sel); err != nil {
+		return errors.New(label + "有误 " + sel + ": " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTMLSourceFetchAll(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<ul class="news">
+			<li data-id="3"><span class="t"> 第三条
+				新闻 </span><a href="/news/3.html">详情</a></li>
+			<li data-id="2"><span class="t">第二条</span><a href="https://other.example.com/2">详情</a></li>
+			<li data-id="2"><span class="t">重复</span><a href="/news/2.html">详情</a></li>
+			<li data-id="1"><span class="t"></span><a href="/news/1.html">无标题</a></li>
+		</ul>`))
+	}))
+	defer srv.Close()
+
+	c := htmlSourceChecker{cfg: HTMLSourceConfig{
+		Name:          "新闻",
+		URL:           srv.URL + "/list/",
+		ItemSelector:  "ul.news li",
+		TitleSelector: ".t",
+		KeyAttr:       "data-id",
+	}}
+	all, err := c.FetchAll(context.Background(), srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []latestItem{
+		{Key: "3", Title: "第三条 新闻", Link: srv.URL + "/news/3.html"},
+		{Key: "2", Title: "第二条", Link: "https://other.example.com/2"},
+	}
+	if len(all) != len(want) {
+		t.Fatalf("all = %+v", all)
+	}
+	for i := range want {
+		if all[i] != want[i] {
+			t.Errorf("all[%d] = %+v, 期望 %+v", i, all[i], want[i])
+		}
+	}
+}
+
+func TestValidateHTMLSourcesSelectors(t *testing.T) {
+	valid := HTMLSourceConfig{Name: "新闻", URL: "https://example.com/", ItemSelector: "ul.news > li", TitleSelector: "a.title", LinkSelector: "a[href]"}
+	if err := validateHTMLSources([]HTMLSourceConfig{valid}); err != nil {
+		t.Fatalf("有效选择器报错: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*HTMLSourceConfig)
+		want   string
+	}{
+		{"列表项", func(c *HTMLSourceConfig) { c.ItemSelector = "li[" }, "列表项选择器有误 li["},
+		{"标题", func(c *HTMLSourceConfig) { c.TitleSelector = "a..title" }, "标题选择器有误 a..title"},
+		{"链接", func(c *HTMLSourceConfig) { c.LinkSelector = "a[href" }, "链接选择器有误 a[href"},
+		{"列表项为空", func(c *HTMLSourceConfig) { c.ItemSelector = " " }, "列表项选择器不能为空"},
+	}
+	for _, tt := range tests {
+		cfg := valid
+		tt.modify(&cfg)
+		err := validateHTMLSources([]HTMLSourceConfig{cfg})
+		if err == nil || !strings.HasPrefix(err.Error(), "新闻: "+tt.want) {
+			t.Errorf("%s: err = %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// JSONSourceConfig 描述一个返回 JSON 列表的接口来源，例如 cycms 的 banner JSON。
+// 路径写法为以点分隔的字段名，可带数组下标，如 data.list、items[0].title；
+// ItemsPath 留空表示根节点就是数组。TitlePath/LinkPath 默认为 cycms banner 的 title 与 href_url，
+// KeyPath 留空时以链接为 key。
+type JSONSourceConfig struct {
+	Name      string `json:"name"`
+	Enabled   bool   `json:"enabled"`
+	URL       string `json:"url"`
+	ItemsPath string `json:"itemsPath"`
+	TitlePath string `json:"titlePath"`
+	LinkPath  string `json:"linkPath"`
+	KeyPath   string `json:"keyPath"`
+	PushHead  string `json:"pushHead"`
+}
+
+type jsonSourceChecker struct {
+	cfg JSONSourceConfig
+}
+
+func (c jsonSourceChecker) Name() string { return strings.TrimSpace(c.cfg.Name) }
+
+func (c jsonSourceChecker) PushHead() string {
+	if head := strings.TrimSpace(c.cfg.PushHead); head != "" {
+		return head
+	}
+	return c.Name() + "有更新了"
+}
+
+var (
+	jsonPathSegmentRe = regexp.MustCompile(`^([^\[\]]*)((?:\[\d+\])*)$`)
+	jsonPathIndexRe   = regexp.MustCompile(`\[(\d+)\]`)
+	// jsonpCallbackRe 匹配 callback({...}); 形式的 JSONP 包装。
+	jsonpCallbackRe = regexp.MustCompile(`^\s*[A-Za-z_$][\w$.]*\s*\(([\s\S]*)\)\s*;?\s*$`)
+)
+
+// jsonPathStep 为路径中的一段：字段名（可为空）加若干数组下标。
+type jsonPathStep struct {
+	field   string
+	indexes []int
+}
+
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return nil, nil
+	}
+
+	var steps []jsonPathStep
+	for _, seg := range strings.Split(path, ".") {
+		m := jsonPathSegmentRe.FindStringSubmatch(seg)
+		if m == nil || (m[1] == "" && m[2] == "") {
+			return nil, errors.New("无效 JSON 路径: " + path)
+		}
+		step := jsonPathStep{field: m[1]}
+		for _, idx := range jsonPathIndexRe.FindAllStringSubmatch(m[2], -1) {
+			n, _ := strconv.Atoi(idx[1])
+			step.indexes = append(step.indexes, n)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// lookupJSONPath 按路径在解码后的 JSON 中取值。
+func lookupJSONPath(v interface{}, steps []jsonPathStep) (interface{}, bool) {
+	for _, step := range steps {
+		if step.field != "" {
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if v, ok = obj[step.field]; !ok {
+				return nil, false
+			}
+		}
+		for _, idx := range step.indexes {
+			arr, ok := v.([]interface{})
+			if !ok || idx >= len(arr) {
+				return nil, false
+			}
+			v = arr[idx]
+		}
+	}
+	return v, true
+}
+
+// jsonScalarString 把字符串、数字、布尔值转换为字符串，其他类型返回空串。
+func jsonScalarString(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return strings.TrimSpace(x)
+	case json.Number:
+		return x.String()
+	case bool:
+		return strconv.FormatBool(x)
+	}
+	return ""
+}
+
+func (c jsonSourceChecker) FetchLatest(ctx context.Context, client *http.Client) (latestItem, error) {
+	all, err := c.FetchAll(ctx, client)
+	if err != nil || len(all) == 0 {
+		return latestItem{}, err
+	}
+	return all[0], nil
+}
+
+func (c jsonSourceChecker) FetchAll(ctx context.Context, client *http.Client) ([]latestItem, error) {
+	itemsPath, err := parseJSONPath(c.cfg.ItemsPath)
+	if err != nil {
+		return nil, err
+	}
+	titlePath, err := parseJSONPath(defaultString(c.cfg.TitlePath, "title"))
+	if err != nil {
+		return nil, err
+	}
+	linkPath, err := parseJSONPath(defaultString(c.cfg.LinkPath, "href_url"))
+	if err != nil {
+		return nil, err
+	}
+	keyPath, err := parseJSONPath(c.cfg.KeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pageURL := strings.TrimSpace(c.cfg.URL)
+	body, err := fetchPage(ctx, client, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	body = bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+	if m := jsonpCallbackRe.FindSubmatch(body); m != nil {
+		body = m[1]
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var root interface{}
+	if err := dec.Decode(&root); err != nil {
+		return nil, err
+	}
+
+	node, ok := lookupJSONPath(root, itemsPath)
+	if !ok {
+		return nil, errors.New("JSON 中未找到列表: " + c.cfg.ItemsPath)
+	}
+	arr, ok := node.([]interface{})
+	if !ok {
+		return nil, errors.New("JSON 路径不是数组: " + c.cfg.ItemsPath)
+	}
+
+	base, _ := url.Parse(pageURL)
+	var out []latestItem
+	seen := map[string]struct{}{}
+	for _, entry := range arr {
+		titleVal, _ := lookupJSONPath(entry, titlePath)
+		linkVal, _ := lookupJSONPath(entry, linkPath)
+		title := jsonScalarString(titleVal)
+		link := resolveLink(base, jsonScalarString(linkVal))
+
+		key := ""
+		if len(keyPath) > 0 {
+			keyVal, _ := lookupJSONPath(entry, keyPath)
+			key = jsonScalarString(keyVal)
+		}
+		if key == "" {
+			key = link
+		}
+		if key == "" {
+			key = title
+		}
+		if key == "" {
+			continue
+		}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, latestItem{Key: key, Title: title, Link: link})
+	}
+	return out, nil
+}
+
+func defaultString(s string, def string) string {
+	if strings.TrimSpace(s) == "" {
+		return def
+	}
+	return s
+}
+
+func validateJSONSources(sources []JSONSourceConfig) error {
+	for _, s := range sources {
+		if err := validateSourceURL(s.URL); err != nil {
+			return errors.New(s.Name + ": " + err.Error())
+		}
+		for _, p := range []string{s.ItemsPath, s.TitlePath, s.LinkPath, s.KeyPath} {
+			if _, err := parseJSONPath(p); err != nil {
+				return errors.New(s.Name + ": " + err.Error())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want []jsonPathStep
+		err  bool
+	}{
+		{"", nil, false},
+		{"$", nil, false},
+		{"data.list", []jsonPathStep{{field: "data"}, {field: "list"}}, false},
+		{"$.items[0].title", []jsonPathStep{{field: "items", indexes: []int{0}}, {field: "title"}}, false},
+		{"[1][2]", []jsonPathStep{{indexes: []int{1, 2}}}, false},
+		{"data..list", nil, true},
+		{"items[x]", nil, true},
+		{"items[0", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseJSONPath(tt.path)
+		if tt.err {
+			if err == nil {
+				t.Errorf("parseJSONPath(%q) 应报错, 得到 %+v", tt.path, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseJSONPath(%q) = %+v, %v, 期望 %+v", tt.path, got, err, tt.want)
+		}
+	}
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	root := map[string]interface{}{
+		"data": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{"title": "第一条"},
+				[]interface{}{"嵌套"},
+			},
+		},
+	}
+	tests := []struct {
+		path string
+		want interface{}
+		ok   bool
+	}{
+		{"data.list[0].title", "第一条", true},
+		{"data.list[1][0]", "嵌套", true},
+		{"data.list[2]", nil, false},
+		{"data.missing", nil, false},
+		{"data.list.title", nil, false},
+		{"data[0]", nil, false},
+	}
+	for _, tt := range tests {
+		steps, err := parseJSONPath(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := lookupJSONPath(root, steps)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("lookupJSONPath(%q) = %v, %v, 期望 %v, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestJSONSourceFetchAll(t *testing.T) {
+	bodies := map[string]string{
+		// cycms banner 默认字段，带 BOM；相对链接按接口地址解析，重复链接只保留第一条。
+		"/banner.json": "\xef\xbb\xbf" + `[
+			{"title": " 国庆活动 ", "href_url": "/act/gq.html"},
+			{"title": "重复", "href_url": "/act/gq.html"},
+			{"title": "外链", "href_url": "https://other.example.com/x"},
+			{"title": "", "href_url": ""}
+		]`,
+		// JSONP 包装，自定义路径，数字 id 作为 key，缺少 id 时退回链接。
+		"/list.js": `cb({"data": {"list": [
+			{"id": 1024, "info": {"name": "维护公告"}, "url": "n/1024.html"},
+			{"info": {"name": "无 id"}, "url": "n/7.html"}
+		]}});`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(bodies[r.URL.Path]))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		cfg  JSONSourceConfig
+		want []latestItem
+	}{
+		{"banner", JSONSourceConfig{Name: "banner", URL: srv.URL + "/banner.json"}, []latestItem{
+			{Key: srv.URL + "/act/gq.html", Title: "国庆活动", Link: srv.URL + "/act/gq.html"},
+			{Key: "https://other.example.com/x", Title: "外链", Link: "https://other.example.com/x"},
+		}},
+		{"jsonp", JSONSourceConfig{Name: "新闻", URL: srv.URL + "/list.js", ItemsPath: "data.list", TitlePath: "info.name", LinkPath: "url", KeyPath: "id"}, []latestItem{
+			{Key: "1024", Title: "维护公告", Link: srv.URL + "/n/1024.html"},
+			{Key: srv.URL + "/n/7.html", Title: "无 id", Link: srv.URL + "/n/7.html"},
+		}},
+	}
+	for _, tt := range tests {
+		all, err := jsonSourceChecker{cfg: tt.cfg}.FetchAll(context.Background(), srv.Client())
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(all, tt.want) {
+			t.Errorf("%s: all = %+v, 期望 %+v", tt.name, all, tt.want)
+		}
+	}
+
+	_, err := jsonSourceChecker{cfg: JSONSourceConfig{Name: "banner", URL: srv.URL + "/banner.json", ItemsPath: "data"}}.FetchAll(context.Background(), srv.Client())
+	if err == nil || !strings.Contains(err.Error(), "JSON 中未找到列表") {
+		t.Errorf("缺少列表路径: err = %v", err)
+	}
+}
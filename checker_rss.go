@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RSSSourceConfig 描述一个 RSS 2.0 或 Atom 订阅源，条目以 guid/id 去重。
+type RSSSourceConfig struct {
+	Name     string `json:"name"`
+	Enabled  bool   `json:"enabled"`
+	URL      string `json:"url"`
+	PushHead string `json:"pushHead"`
+}
+
+type rssSourceChecker struct {
+	cfg RSSSourceConfig
+}
+
+func (c rssSourceChecker) Name() string { return strings.TrimSpace(c.cfg.Name) }
+
+func (c rssSourceChecker) PushHead() string {
+	if head := strings.TrimSpace(c.cfg.PushHead); head != "" {
+		return head
+	}
+	return c.Name() + "有更新了"
+}
+
+// rssDocument 同时覆盖 RSS 2.0（rss/channel/item）、RSS 1.0（rdf:RDF/item）与 Atom（feed/entry）。
+type rssDocument struct {
+	XMLName xml.Name
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items   []rssItem   `xml:"item"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
+	GUID  string `xml:"guid"`
+}
+
+type atomEntry struct {
+	Title string     `xml:"title"`
+	ID    string     `xml:"id"`
+	Links []atomLink `xml:"link"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// href 返回 rel 为 alternate（或未填写）的链接，没有时取第一个。
+func (e atomEntry) href() string {
+	for _, l := range e.Links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return l.Href
+		}
+	}
+	if len(e.Links) > 0 {
+		return e.Links[0].Href
+	}
+	return ""
+}
+
+// rssCharsetReader 只接受 UTF-8 编码的订阅源，其他编码直接报错，避免解析出乱码。
+func rssCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "", "utf-8", "utf8", "us-ascii":
+		return input, nil
+	}
+	return nil, errors.New("不支持的订阅源编码: " + charset)
+}
+
+func (c rssSourceChecker) FetchLatest(ctx context.Context, client *http.Client) (latestItem, error) {
+	all, err := c.FetchAll(ctx, client)
+	if err != nil || len(all) == 0 {
+		return latestItem{}, err
+	}
+	return all[0], nil
+}
+
+func (c rssSourceChecker) FetchAll(ctx context.Context, client *http.Client) ([]latestItem, error) {
+	feedURL := strings.TrimSpace(c.cfg.URL)
+	body, err := fetchPage(ctx, client, feedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	dec.CharsetReader = rssCharsetReader
+	var doc rssDocument
+	if err := dec.Decode(&doc); err != nil {
+		return nil, errors.New("订阅源解析失败: " + err.Error())
+	}
+
+	var raw []latestItem
+	switch doc.XMLName.Local {
+	case "rss", "RDF":
+		items := doc.Channel.Items
+		if len(items) == 0 {
+			items = doc.Items
+		}
+		for _, it := range items {
+			raw = append(raw, latestItem{Key: strings.TrimSpace(it.GUID), Title: it.Title, Link: it.Link})
+		}
+	case "feed":
+		for _, e := range doc.Entries {
+			raw = append(raw, latestItem{Key: strings.TrimSpace(e.ID), Title: e.Title, Link: e.href()})
+		}
+	default:
+		return nil, errors.New("不是 RSS 或 Atom 订阅源: " + doc.XMLName.Local)
+	}
+
+	base, _ := url.Parse(feedURL)
+	var out []latestItem
+	seen := map[string]struct{}{}
+	for _, it := range raw {
+		it.Title = strings.Join(strings.Fields(it.Title), " ")
+		it.Link = resolveLink(base, it.Link)
+		if it.Key == "" {
+			it.Key = it.Link
+		}
+		if it.Key == "" {
+			it.Key = it.Title
+		}
+		if it.Key == "" || it.Title == "" {
+			continue
+		}
+		if _, dup := seen[it.Key]; dup {
+			continue
+		}
+		seen[it.Key] = struct{}{}
+		out = append(out, it)
+	}
+	return out, nil
+}
+
+func validateRSSSources(sources []RSSSourceConfig) error {
+	for _, s := range sources {
+		if err := validateSourceURL(s.URL); err != nil {
+			return errors.New(s.Name + ": " + err.Error())
+		}
+	}
+	return nil
+}
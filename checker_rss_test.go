@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRSSSourceFetchAll(t *testing.T) {
+	bodies := map[string]string{
+		// RSS 2.0：缺少 guid 时以链接为 key，相对链接按订阅源地址解析，无标题的条目跳过。
+		"/feed/rss.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>新闻</title>
+	<item><title> 停服
+		维护公告 </title><link>/news/3.html</link><guid isPermaLink="false">n-3</guid></item>
+	<item><title>版本更新</title><link>news/2.html</link></item>
+	<item><title>重复</title><link>/x.html</link><guid>n-3</guid></item>
+	<item><title></title><link>/news/1.html</link></item>
+</channel></rss>`,
+		// Atom：优先取 rel=alternate 的链接，缺少 id 时同样以链接为 key。
+		"/feed/atom.xml": `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<entry><title>国庆活动</title><id>tag:example.com,2026:act-1</id>
+		<link rel="self" href="/api/act/1"/><link rel="alternate" href="/act/1.html"/></entry>
+	<entry><title>只有 self 链接</title><link rel="self" href="https://other.example.com/2"/></entry>
+</feed>`,
+		// RSS 1.0：条目与 channel 平级。
+		"/feed/rdf.xml": `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+	<channel><title>公告</title></channel>
+	<item><title>RDF 条目</title><link>https://example.com/rdf/1</link></item>
+</rdf:RDF>`,
+		"/feed/gbk.xml":  `<?xml version="1.0" encoding="GBK"?><rss><channel></channel></rss>`,
+		"/feed/page.xml": `<html><body>不是订阅源</body></html>`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(bodies[r.URL.Path]))
+	}))
+	defer srv.Close()
+
+	fetch := func(path string) ([]latestItem, error) {
+		c := rssSourceChecker{cfg: RSSSourceConfig{Name: "订阅", URL: srv.URL + path}}
+		return c.FetchAll(context.Background(), srv.Client())
+	}
+
+	tests := []struct {
+		path string
+		want []latestItem
+	}{
+		{"/feed/rss.xml", []latestItem{
+			{Key: "n-3", Title: "停服 维护公告", Link: srv.URL + "/news/3.html"},
+			{Key: srv.URL + "/feed/news/2.html", Title: "版本更新", Link: srv.URL + "/feed/news/2.html"},
+		}},
+		{"/feed/atom.xml", []latestItem{
+			{Key: "tag:example.com,2026:act-1", Title: "国庆活动", Link: srv.URL + "/act/1.html"},
+			{Key: "https://other.example.com/2", Title: "只有 self 链接", Link: "https://other.example.com/2"},
+		}},
+		{"/feed/rdf.xml", []latestItem{
+			{Key: "https://example.com/rdf/1", Title: "RDF 条目", Link: "https://example.com/rdf/1"},
+		}},
+	}
+	for _, tt := range tests {
+		all, err := fetch(tt.path)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		if !reflect.DeepEqual(all, tt.want) {
+			t.Errorf("%s: all = %+v, 期望 %+v", tt.path, all, tt.want)
+		}
+	}
+
+	for path, want := range map[string]string{
+		"/feed/gbk.xml":  "不支持的订阅源编码: GBK",
+		"/feed/page.xml": "不是 RSS 或 Atom 订阅源: html",
+	} {
+		if _, err := fetch(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, 期望包含 %q", path, err, want)
+		}
+	}
+}
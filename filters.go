@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// 过滤规则的动作取值。
+const (
+	filterActionAll  = "all"  // 打开链接并推送
+	filterActionOpen = "open" // 仅打开链接
+	filterActionPush = "push" // 仅推送
+	filterActionLog  = "log"  // 仅记录日志
+)
+
+// FilterRule 为某个来源的关键词过滤规则。
+// Include 中任一条命中才算通过（为空表示全部通过），Exclude 中任一条命中即不通过。
+// 规则以 "re:" 开头时按正则匹配，否则按关键词包含匹配；两者都忽略大小写与全角/半角差异。
+type FilterRule struct {
+	Source  string   `json:"source"`
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+	// Action 为通过时的动作，默认 all；Else 为未通过时的动作，默认 log。
+	Action string `json:"action"`
+	Else   string `json:"else"`
+}
+
+// filterDecision 为过滤规则对一个新条目的处理结果。
+type filterDecision struct {
+	Open    bool
+	Push    bool
+	Matched []string
+}
+
+type compiledPattern struct {
+	raw     string
+	keyword string
+	re      *regexp.Regexp
+}
+
+// foldWidth 把全角字符转换为半角，并统一转为小写，用于不区分大小写/全半角的匹配。
+func foldWidth(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '　':
+			return ' '
+		case r >= '！' && r <= '～':
+			r -= 0xFEE0
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+func compilePattern(raw string) (compiledPattern, error) {
+	raw = strings.TrimSpace(raw)
+	if expr, ok := strings.CutPrefix(raw, "re:"); ok {
+		// 正则表达式本身不能整体转小写（会改变 \D、\S 等含义），这里只做全半角转换并加 (?i)。
+		expr = strings.Map(func(r rune) rune {
+			if r >= '！' && r <= '～' {
+				return r - 0xFEE0
+			}
+			return r
+		}, strings.TrimSpace(expr))
+		re, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return compiledPattern{}, errors.New("正则表达式有误 " + raw + ": " + err.Error())
+		}
+		return compiledPattern{raw: raw, re: re}, nil
+	}
+	return compiledPattern{raw: raw, keyword: foldWidth(raw)}, nil
+}
+
+// match 返回命中的内容：关键词返回原始关键词，正则返回匹配到的文本。
+func (p compiledPattern) match(folded string) (string, bool) {
+	if p.re != nil {
+		loc := p.re.FindStringIndex(folded)
+		if loc == nil {
+			return "", false
+		}
+		return folded[loc[0]:loc[1]], true
+	}
+	if p.keyword != "" && strings.Contains(folded, p.keyword) {
+		return p.raw, true
+	}
+	return "", false
+}
+
+func compilePatterns(raws []string) ([]compiledPattern, error) {
+	var out []compiledPattern
+	for _, raw := range raws {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		p, err := compilePattern(raw)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func normalizeFilterAction(action string, def string) (string, error) {
+	action = strings.ToLower(strings.TrimSpace(action))
+	switch action {
+	case "":
+		return def, nil
+	case filterActionAll, filterActionOpen, filterActionPush, filterActionLog:
+		return action, nil
+	}
+	return "", errors.New("无效过滤动作: " + action)
+}
+
+func decisionFor(action string, matched []string) filterDecision {
+	return filterDecision{
+		Open:    action == filterActionAll || action == filterActionOpen,
+		Push:    action == filterActionAll || action == filterActionPush,
+		Matched: matched,
+	}
+}
+
+// validateFilterRules 在保存设置时校验正则与动作取值。
+func validateFilterRules(rules []FilterRule) error {
+	for _, r := range rules {
+		name := strings.TrimSpace(r.Source)
+		if name == "" {
+			name = "全部来源"
+		}
+		if _, err := compilePatterns(r.Include); err != nil {
+			return errors.New(name + "过滤规则有误: " + err.Error())
+		}
+		if _, err := compilePatterns(r.Exclude); err != nil {
+			return errors.New(name + "过滤规则有误: " + err.Error())
+		}
+		if _, err := normalizeFilterAction(r.Action, filterActionAll); err != nil {
+			return errors.New(name + "过滤规则有误: " + err.Error())
+		}
+		if _, err := normalizeFilterAction(r.Else, filterActionLog); err != nil {
+			return errors.New(name + "过滤规则有误: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// findFilterRule 返回适用于来源的规则：优先来源名称完全一致的规则，其次为不限来源的规则。
+func findFilterRule(rules []FilterRule, source string) (FilterRule, bool) {
+	var generic *FilterRule
+	for i := range rules {
+		s := strings.TrimSpace(rules[i].Source)
+		if s == source {
+			return rules[i], true
+		}
+		if s == "" && generic == nil {
+			generic = &rules[i]
+		}
+	}
+	if generic != nil {
+		return *generic, true
+	}
+	return FilterRule{}, false
+}
+
+// evaluateFilters 决定新条目是打开链接、推送还是仅记录。没有适用规则时打开并推送。
+func evaluateFilters(rules []FilterRule, source string, title string) filterDecision {
+	rule, ok := findFilterRule(rules, source)
+	if !ok {
+		return decisionFor(filterActionAll, nil)
+	}
+
+	pass, _ := normalizeFilterAction(rule.Action, filterActionAll)
+	fail, _ := normalizeFilterAction(rule.Else, filterActionLog)
+	// 已在保存时校验，这里忽略编译错误：编译失败的一组规则按空处理。
+	include, _ := compilePatterns(rule.Include)
+	exclude, _ := compilePatterns(rule.Exclude)
+
+	folded := foldWidth(title)
+	for _, p := range exclude {
+		if _, ok := p.match(folded); ok {
+			return decisionFor(fail, nil)
+		}
+	}
+
+	if len(include) == 0 {
+		return decisionFor(pass, nil)
+	}
+	var matched []string
+	for _, p := range include {
+		if hit, ok := p.match(folded); ok {
+			matched = append(matched, hit)
+		}
+	}
+	if len(matched) == 0 {
+		return decisionFor(fail, nil)
+	}
+	return decisionFor(pass, matched)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFoldWidth(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"ＧＭ公告", "gm公告"},
+		{"（维护）　通知", "(维护) 通知"},
+		{"CDK：ABC１２３", "cdk:abc123"},
+	}
+	for _, tt := range tests {
+		if got := foldWidth(tt.in); got != tt.want {
+			t.Errorf("foldWidth(%q) = %q, 期望 %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateFilters(t *testing.T) {
+	rules := []FilterRule{
+		{Include: []string{"维护", "re:v\\d+\\.\\d+"}, Exclude: []string{"测试服"}},
+		{Source: "论坛", Include: []string{"GM"}, Action: filterActionPush, Else: filterActionOpen},
+		{Source: "活动", Exclude: []string{"re:^【已结束】"}},
+	}
+	tests := []struct {
+		name    string
+		source  string
+		title   string
+		open    bool
+		push    bool
+		matched []string
+	}{
+		{"通用规则命中关键词", "公告", "10月16日停服维护公告", true, true, []string{"维护"}},
+		{"正则按半角小写匹配", "公告", "客户端更新至Ｖ２.３说明", true, true, []string{"v2.3"}},
+		{"同时命中多条", "公告", "维护后更新至 V3.1", true, true, []string{"维护", "v3.1"}},
+		{"未命中按 else 仅记录", "公告", "周末活动预告", false, false, nil},
+		{"排除优先于包含", "公告", "测试服维护公告", false, false, nil},
+		{"来源规则优先于通用规则", "论坛", "维护帖", true, false, nil},
+		{"关键词不区分全半角与大小写", "论坛", "ｇｍ发布了新帖", false, true, []string{"GM"}},
+		{"只有排除时其余全部通过", "活动", "国庆活动", true, true, nil},
+		{"排除正则", "活动", "【已结束】中秋活动", false, false, nil},
+	}
+	for _, tt := range tests {
+		d := evaluateFilters(rules, tt.source, tt.title)
+		if d.Open != tt.open || d.Push != tt.push || !slices.Equal(d.Matched, tt.matched) {
+			t.Errorf("%s: %+v, 期望 open=%v push=%v matched=%v", tt.name, d, tt.open, tt.push, tt.matched)
+		}
+	}
+
+	if d := evaluateFilters(nil, "公告", "任意标题"); !d.Open || !d.Push {
+		t.Errorf("没有规则时应打开并推送: %+v", d)
+	}
+}
+
+func TestValidateFilterRules(t *testing.T) {
+	tests := []struct {
+		rule FilterRule
+		want string
+	}{
+		{FilterRule{Include: []string{"维护", "re:v\\d+"}}, ""},
+		{FilterRule{Source: "公告", Include: []string{"re:(维护"}}, "公告过滤规则有误: 正则表达式有误 re:(维护"},
+		{FilterRule{Exclude: []string{"re:[a-"}}, "全部来源过滤规则有误"},
+		{FilterRule{Action: "notify"}, "无效过滤动作: notify"},
+		{FilterRule{Else: "PUSH"}, ""},
+	}
+	for _, tt := range tests {
+		err := validateFilterRules([]FilterRule{tt.rule})
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%+v: 不应报错: %v", tt.rule, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%+v: err = %v, 期望包含 %q", tt.rule, err, tt.want)
+		}
+	}
+}
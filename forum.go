@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const forumDisplayURL = "https://bbs.tlhj.changyou.com/forum.php?mod=forumdisplay&fid="
+
+// ForumBoardConfig 描述一个要监控的 Discuz 版块，每个版块独立记录已见帖子。
+// OfficialOnly 开启后只提醒 OfficialAuthors 中的账号或 OfficialGroups 中的用户组所发的帖子，
+// 其余新帖只记入历史。
+type ForumBoardConfig struct {
+	FID             int      `json:"fid"`
+	Name            string   `json:"name"`
+	PushHead        string   `json:"pushHead"`
+	Enabled         bool     `json:"enabled"`
+	OfficialOnly    bool     `json:"officialOnly"`
+	OfficialAuthors []string `json:"officialAuthors"`
+	OfficialGroups  []string `json:"officialGroups"`
+}
+
+// defaultForumBoard 为未配置版块时监控的综合讨论区，名称沿用原来的“论坛”。
+func defaultForumBoard() ForumBoardConfig {
+	return ForumBoardConfig{FID: 2, Name: "论坛", PushHead: "天龙论坛有新帖了", Enabled: true}
+}
+
+// effectiveForumBoards 返回实际使用的版块列表，未配置时为默认版块。
+func effectiveForumBoards(boards []ForumBoardConfig) []ForumBoardConfig {
+	if len(boards) == 0 {
+		return []ForumBoardConfig{defaultForumBoard()}
+	}
+	return append([]ForumBoardConfig(nil), boards...)
+}
+
+func forumBoardURL(fid int) string {
+	return forumDisplayURL + strconv.Itoa(fid)
+}
+
+// forumCheckersLocked 返回启用的论坛版块，调用方需持有 m.mu。
+func (m *Monitor) forumCheckersLocked() []checker {
+	var out []checker
+	for _, b := range effectiveForumBoards(m.forumBoards) {
+		if b.Enabled {
+			out = append(out, forumChecker{board: b})
+		}
+	}
+	return out
+}
+
+// primaryForumStateLocked 返回第一个版块的状态，用于状态栏中原有的论坛一栏，调用方需持有 m.mu。
+func (m *Monitor) primaryForumStateLocked() sourceState {
+	boards := effectiveForumBoards(m.forumBoards)
+	return m.sourceStates[strings.TrimSpace(boards[0].Name)]
+}
+
+// migrateLegacyForumLocked 把旧版本只监控 fid=2 时记录的最新帖子标题与链接迁移到对应版块名下，
+// 供状态栏显示，调用方需持有 m.mu。旧版本只记录了列表中的第一个帖子（通常是置顶帖），
+// 据此无法判断哪些帖子已见，因此不迁移已见状态，首次检查时按整页重新记录基线。
+func (m *Monitor) migrateLegacyForumLocked(s persistedSettings) {
+	title := strings.TrimSpace(s.LastForumTitle)
+	link := strings.TrimSpace(s.LastForumLink)
+	if title == "" && link == "" {
+		return
+	}
+	for _, b := range effectiveForumBoards(m.forumBoards) {
+		if b.FID != defaultForumBoard().FID {
+			continue
+		}
+		name := strings.TrimSpace(b.Name)
+		if _, ok := m.sourceStates[name]; ok {
+			return
+		}
+		if m.sourceStates == nil {
+			m.sourceStates = map[string]sourceState{}
+		}
+		m.sourceStates[name] = sourceState{LastTitle: title, LastLink: link}
+		return
+	}
+}
+
+// checkForumBoard 处理一个版块的帖子列表：首次运行只记录基线；之后按 tid 判断新帖，
+// 按发帖顺序逐条处理。
+func (m *Monitor) checkForumBoard(ctx context.Context, appCtx context.Context, run checkRun, c forumChecker, all []latestItem) {
+	name := c.Name()
+
+	m.mu.Lock()
+	st := m.sourceStates[name]
+	st.SeenKeys = append([]string(nil), st.SeenKeys...)
+	m.mu.Unlock()
+
+	if len(st.SeenKeys) == 0 {
+		st.SeenKeys = itemKeys(all)
+		st.LastTitle = all[0].Title
+		st.LastLink = all[0].Link
+		m.setSourceState(name, st)
+		m.emitLog(appCtx, "INFO", "已获取当前最新"+name+"帖子(基线): "+all[0].Title)
+		return
+	}
+
+	// tid 递增分配：未见过但 tid 不大于已见最大 tid 的，是旧帖被回复顶上来或新置顶，
+	// 只记入已见集合，不当作新帖。
+	maxTID := maxThreadID(st.SeenKeys)
+	unseen, updated := diffSeen(all, st.SeenKeys)
+	var newItems []latestItem
+	for _, it := range unseen {
+		if tid, err := strconv.Atoi(it.Key); err == nil && tid <= maxTID {
+			m.emitLog(appCtx, "INFO", name+"出现未见过的旧帖（可能被回复顶起、新置顶或审核后显示），仅记为已见: "+it.Title)
+			continue
+		}
+		newItems = append(newItems, it)
+	}
+	sort.SliceStable(newItems, func(i, j int) bool {
+		a, _ := strconv.Atoi(newItems[i].Key)
+		b, _ := strconv.Atoi(newItems[j].Key)
+		return a < b
+	})
+
+	if len(unseen) > 0 {
+		st.SeenKeys = updated
+		if len(newItems) > 0 {
+			newest := newItems[len(newItems)-1]
+			st.LastTitle = newest.Title
+			st.LastLink = newest.Link
+		}
+		m.setSourceState(name, st)
+	}
+
+	if len(newItems) == 0 {
+		m.emitLog(appCtx, "INFO", name+"未发现新帖: "+all[0].Title)
+		return
+	}
+	for _, it := range newItems {
+		if c.board.OfficialOnly && !m.isOfficialThread(ctx, appCtx, c.board, it) {
+			m.emitLog(appCtx, "INFO", name+"新帖不是官方账号发布，仅记录: "+it.Title)
+			m.recordHistory(HistoryEntry{
+				Source:     name,
+				Title:      it.Title,
+				Link:       it.Link,
+				Author:     it.Author,
+				Note:       "非官方作者",
+				DetectedAt: run.now,
+			})
+			continue
+		}
+		m.emitLog(appCtx, "INFO", "检测到"+name+"新帖: "+it.Title)
+		m.deliver(ctx, appCtx, run, c, it)
+	}
+}
+
+// isOfficialThread 判断帖子是否由官方账号发布：先按作者名单匹配，
+// 配置了用户组时再打开帖子读取楼主的用户组。
+func (m *Monitor) isOfficialThread(ctx context.Context, appCtx context.Context, b ForumBoardConfig, it latestItem) bool {
+	if isWatchedAuthor(b.OfficialAuthors, it.Author) {
+		return true
+	}
+	if len(b.OfficialGroups) == 0 || strings.TrimSpace(it.Link) == "" {
+		return false
+	}
+	group, err := fetchThreadAuthorGroup(ctx, m.httpClient, it.Link)
+	if err != nil {
+		m.emitLog(appCtx, "WARN", "读取发帖人用户组失败: "+err.Error())
+		return false
+	}
+	return isWatchedAuthor(b.OfficialGroups, group)
+}
+
+// fetchThreadAuthorGroup 打开帖子首页，返回楼主的用户组名称（如“管理员”）。
+func fetchThreadAuthorGroup(ctx context.Context, client *http.Client, link string) (string, error) {
+	doc, err := fetchDocument(ctx, client, link)
+	if err != nil {
+		return "", err
+	}
+	first := doc.Find("#postlist > div[id^='post_']").First()
+	return strings.TrimSpace(first.Find(".pls a[href*='ac=usergroup']").First().Text()), nil
+}
+
+// forumBoardNames 返回实际使用的版块名称，用于保存设置时校验重名。
+func forumBoardNames(boards []ForumBoardConfig) []string {
+	var names []string
+	for _, b := range effectiveForumBoards(boards) {
+		names = append(names, strings.TrimSpace(b.Name))
+	}
+	return names
+}
+
+func validateForumBoards(boards []ForumBoardConfig) error {
+	used := map[int]bool{}
+	for _, b := range boards {
+		if b.FID <= 0 {
+			return errors.New(b.Name + ": 版块 fid 需为正整数")
+		}
+		if used[b.FID] {
+			return errors.New("版块重复: fid=" + strconv.Itoa(b.FID))
+		}
+		used[b.FID] = true
+		if b.OfficialOnly && len(b.OfficialAuthors) == 0 && len(b.OfficialGroups) == 0 {
+			return errors.New(b.Name + ": 仅提醒官方帖子时需填写官方账号或用户组")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixtureTransport 对所有请求返回同一个页面，用于解析固定的论坛列表。
+type fixtureTransport struct{ body string }
+
+func (t fixtureTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		Body:       io.NopCloser(strings.NewReader(t.body)),
+		Request:    r,
+	}, nil
+}
+
+// forumRow 生成帖子列表中的一行，kind 为 stick 或 normal。
+func forumRow(kind string, tid int, title string) string {
+	return fmt.Sprintf(`<tbody id="%sthread_%d"><tr><th><a class="s xst" href="forum.php?mod=viewthread&tid=%d">%s</a></th>`+
+		`<td class="by"><cite><a>玩家</a></cite></td></tr></tbody>`, kind, tid, tid, title)
+}
+
+func forumPage(rows ...string) string {
+	return `<html><body><table id="threadlisttableid">` + strings.Join(rows, "") + `</table></body></html>`
+}
+
+func fetchForumFixture(t *testing.T, page string) []latestItem {
+	t.Helper()
+	c := forumChecker{board: defaultForumBoard()}
+	all, err := c.FetchAll(context.Background(), &http.Client{Transport: fixtureTransport{page}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return all
+}
+
+func historyTitles(m *Monitor) []string {
+	var out []string
+	for _, e := range m.History() {
+		out = append(out, e.Title)
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestMigrateLegacyForumRebaselines(t *testing.T) {
+	useTempConfigDir(t)
+
+	m := NewMonitor()
+	m.mu.Lock()
+	// 旧版本记录的是列表中的第一个帖子，即置顶帖。
+	m.migrateLegacyForumLocked(persistedSettings{
+		LastForumTitle: "【置顶】论坛版规",
+		LastForumLink:  "https://bbs.changyou.com/forum.php?mod=viewthread&tid=100",
+	})
+	run := m.newCheckRunLocked(time.Now())
+	m.mu.Unlock()
+
+	if got := m.Status().LastForumTitle; got != "【置顶】论坛版规" {
+		t.Fatalf("迁移后状态栏论坛标题 = %q", got)
+	}
+
+	page := forumPage(
+		forumRow("stick", 100, "【置顶】论坛版规"),
+		forumRow("normal", 2004, "第四帖"),
+		forumRow("normal", 2003, "第三帖"),
+		forumRow("normal", 2002, "第二帖"),
+		forumRow("normal", 2001, "第一帖"),
+	)
+	name := defaultForumBoard().Name
+	m.checkForumBoard(context.Background(), nil, run, forumChecker{board: defaultForumBoard()}, fetchForumFixture(t, page))
+
+	if got := historyTitles(m); len(got) != 0 {
+		t.Fatalf("迁移后的首次检查不应报告新帖: %v", got)
+	}
+	keys := seenKeys(m, name)
+	slices.Sort(keys)
+	if want := []string{"100", "2001", "2002", "2003", "2004"}; !slices.Equal(keys, want) {
+		t.Fatalf("基线 = %v, 期望 %v", keys, want)
+	}
+}
+
+func TestCheckForumBoardDiff(t *testing.T) {
+	useTempConfigDir(t)
+
+	m := NewMonitor()
+	c := forumChecker{board: defaultForumBoard()}
+	steps := []struct {
+		name string
+		page string
+		// want 为这一步应按发帖顺序报告的新帖
+		want []string
+	}{
+		{
+			name: "首次检查只记录基线",
+			page: forumPage(forumRow("stick", 100, "版规"), forumRow("normal", 2003, "帖3"), forumRow("normal", 2002, "帖2")),
+		},
+		{
+			name: "新帖",
+			page: forumPage(forumRow("stick", 100, "版规"), forumRow("normal", 2004, "帖4"), forumRow("normal", 2003, "帖3")),
+			want: []string{"帖4"},
+		},
+		{
+			name: "旧帖被回复顶回第一页",
+			page: forumPage(forumRow("stick", 100, "版规"), forumRow("normal", 1500, "旧帖"), forumRow("normal", 2004, "帖4")),
+		},
+		{
+			name: "旧帖被设为置顶",
+			page: forumPage(forumRow("stick", 1200, "新置顶"), forumRow("stick", 100, "版规"), forumRow("normal", 2004, "帖4")),
+		},
+		{
+			name: "多个新帖按 tid 从小到大报告",
+			page: forumPage(forumRow("stick", 100, "版规"), forumRow("normal", 2006, "帖6"), forumRow("normal", 2005, "帖5"), forumRow("normal", 2004, "帖4")),
+			want: []string{"帖5", "帖6"},
+		},
+	}
+
+	reported := 0
+	for _, step := range steps {
+		m.mu.Lock()
+		run := m.newCheckRunLocked(time.Now())
+		m.mu.Unlock()
+		all := fetchForumFixture(t, step.page)
+		m.checkForumBoard(context.Background(), nil, run, c, all)
+
+		// 历史最新的在前，取出这一步新增的条目并恢复为检测顺序。
+		history := m.History()
+		var got []string
+		for i := len(history) - reported - 1; i >= 0; i-- {
+			got = append(got, history[i].Title)
+		}
+		reported = len(history)
+		if !slices.Equal(got, step.want) {
+			t.Errorf("%s: 报告 %v, 期望 %v", step.name, got, step.want)
+		}
+		for _, it := range all {
+			if !slices.Contains(seenKeys(m, c.Name()), it.Key) {
+				t.Errorf("%s: %s 未记入已见", step.name, it.Title)
+			}
+		}
+	}
+}
+
+func TestCheckForumBoardOfficialOnly(t *testing.T) {
+	useTempConfigDir(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		group := map[string]string{"/t2002": "管理员", "/t2003": "江湖少侠"}[r.URL.Path]
+		fmt.Fprintf(w, `<div id="postlist"><div id="post_1"><div class="pls"><a href="home.php?mod=spacecp&ac=usergroup&gid=1">%s</a></div></div></div>`, group)
+	}))
+	defer srv.Close()
+
+	m := NewMonitor()
+	m.httpClient = srv.Client()
+	board := defaultForumBoard()
+	board.OfficialOnly = true
+	board.OfficialAuthors = []string{"天龙GM"}
+	board.OfficialGroups = []string{"管理员"}
+	c := forumChecker{board: board}
+
+	thread := func(tid int, author string) latestItem {
+		key := strconv.Itoa(tid)
+		return latestItem{Key: key, Title: "帖" + key, Link: srv.URL + "/t" + key, Author: author}
+	}
+	m.mu.Lock()
+	run := m.newCheckRunLocked(time.Now())
+	m.mu.Unlock()
+	m.checkForumBoard(context.Background(), nil, run, c, []latestItem{thread(2000, "玩家")})
+	m.checkForumBoard(context.Background(), nil, run, c, []latestItem{
+		thread(2003, "玩家乙"),
+		thread(2002, "玩家甲"),
+		thread(2001, "天龙ＧＭ"),
+		thread(2000, "玩家"),
+	})
+
+	want := map[string]struct {
+		notified bool
+		note     string
+	}{
+		"帖2001": {true, ""},
+		"帖2002": {true, ""},
+		"帖2003": {false, "非官方作者"},
+	}
+	history := m.History()
+	if len(history) != len(want) {
+		t.Fatalf("history = %+v", history)
+	}
+	for _, e := range history {
+		w, ok := want[e.Title]
+		if !ok || e.Notified != w.notified || e.Note != w.note {
+			t.Errorf("%s: notified=%v note=%q, 期望 %+v", e.Title, e.Notified, e.Note, w)
+		}
+	}
+}
+
+func TestValidateForumBoards(t *testing.T) {
+	tests := []struct {
+		boards []ForumBoardConfig
+		want   string
+	}{
+		{[]ForumBoardConfig{{FID: 2, Name: "综合"}, {FID: 5, Name: "官方", OfficialOnly: true, OfficialGroups: []string{"管理员"}}}, ""},
+		{[]ForumBoardConfig{{FID: 0, Name: "综合"}}, "综合: 版块 fid 需为正整数"},
+		{[]ForumBoardConfig{{FID: 2, Name: "综合"}, {FID: 2, Name: "重复"}}, "版块重复: fid=2"},
+		{[]ForumBoardConfig{{FID: 5, Name: "官方", OfficialOnly: true}}, "官方: 仅提醒官方帖子时需填写官方账号或用户组"},
+	}
+	for _, tt := range tests {
+		err := validateForumBoards(tt.boards)
+		if (tt.want == "" && err != nil) || (tt.want != "" && (err == nil || err.Error() != tt.want)) {
+			t.Errorf("%+v: err = %v, 期望 %q", tt.boards, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ForumThreadConfig 描述一个需要关注回复的论坛帖子，如补偿帖、BUG 修复帖。
+// Authors 为空时回复数或最后回复时间变化即提醒；不为空时只在这些作者（如 GM 账号）发帖时提醒。
+type ForumThreadConfig struct {
+	Name     string   `json:"name"`
+	Enabled  bool     `json:"enabled"`
+	URL      string   `json:"url"`
+	Authors  []string `json:"authors"`
+	PushHead string   `json:"pushHead"`
+}
+
+type threadChecker struct {
+	cfg ForumThreadConfig
+}
+
+func (c threadChecker) Name() string { return strings.TrimSpace(c.cfg.Name) }
+
+func (c threadChecker) PushHead() string {
+	if head := strings.TrimSpace(c.cfg.PushHead); head != "" {
+		return head
+	}
+	return c.Name() + "有新回复"
+}
+
+// forumPost 为帖子页中的一楼。
+type forumPost struct {
+	PID    string
+	Author string
+	Time   string
+	Link   string
+}
+
+// forumThread 为帖子的标题、回复数与最后一页的各楼。
+type forumThread struct {
+	Title   string
+	Replies int
+	Posts   []forumPost
+}
+
+var (
+	forumPostIDRe  = regexp.MustCompile(`^post_(\d+)$`)
+	forumPageNumRe = regexp.MustCompile(`\d+`)
+)
+
+func (c threadChecker) FetchLatest(ctx context.Context, client *http.Client) (latestItem, error) {
+	t, err := c.fetchThread(ctx, client)
+	if err != nil || len(t.Posts) == 0 {
+		return latestItem{}, err
+	}
+	p := t.Posts[len(t.Posts)-1]
+	return latestItem{Key: p.PID, Title: t.Title, Link: p.Link}, nil
+}
+
+// fetchThread 读取帖子首页，按分页跳到最后一页，返回最后一页上的各楼。
+func (c threadChecker) fetchThread(ctx context.Context, client *http.Client) (forumThread, error) {
+	threadURL := strings.TrimSpace(c.cfg.URL)
+	doc, err := fetchDocument(ctx, client, threadURL)
+	if err != nil {
+		return forumThread{}, err
+	}
+	t := forumThread{
+		Title:   strings.Join(strings.Fields(doc.Find("#thread_subject").First().Text()), " "),
+		Replies: forumThreadReplies(doc),
+	}
+
+	base, _ := url.Parse(threadURL)
+	if last := forumLastPageURL(doc, base); last != "" {
+		lastDoc, err := fetchDocument(ctx, client, last)
+		if err != nil {
+			return forumThread{}, err
+		}
+		if n := forumThreadReplies(lastDoc); n > 0 {
+			t.Replies = n
+		}
+		doc = lastDoc
+		base, _ = url.Parse(last)
+	}
+
+	tid := forumThreadID(threadURL)
+	doc.Find("#postlist > div[id^='post_']").Each(func(_ int, sel *goquery.Selection) {
+		id, _ := sel.Attr("id")
+		m := forumPostIDRe.FindStringSubmatch(id)
+		if m == nil {
+			return
+		}
+		author := strings.TrimSpace(sel.Find(".authi a.xw1").First().Text())
+		if author == "" {
+			author = strings.TrimSpace(sel.Find(".pls .authi a").First().Text())
+		}
+		posted := sel.Find("em[id^='authorposton']").First()
+		postedAt, ok := posted.Find("span[title]").First().Attr("title")
+		if !ok {
+			postedAt = strings.TrimPrefix(strings.TrimSpace(posted.Text()), "发表于")
+		}
+		link := resolveLink(base, "forum.php?mod=redirect&goto=findpost&ptid="+tid+"&pid="+m[1])
+		t.Posts = append(t.Posts, forumPost{PID: m[1], Author: author, Time: strings.TrimSpace(postedAt), Link: link})
+	})
+	if t.Title == "" {
+		return forumThread{}, errors.New("未解析到帖子标题，可能需要登录或帖子已删除")
+	}
+	return t, nil
+}
+
+// fetchDocument 请求页面并解析为 HTML 文档。
+func fetchDocument(ctx context.Context, client *http.Client, pageURL string) (*goquery.Document, error) {
+	body, err := fetchPage(ctx, client, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return goquery.NewDocumentFromReader(bytes.NewReader(body))
+}
+
+// forumThreadReplies 解析帖子头部“查看: x | 回复: y”中的回复数，未找到时返回 0。
+func forumThreadReplies(doc *goquery.Document) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(doc.Find(".hm.ptn span.xi1").Eq(1).Text()))
+	return n
+}
+
+// forumLastPageURL 从分页中找到页码最大的链接；已在最后一页或没有分页时返回空串。
+func forumLastPageURL(doc *goquery.Document, base *url.URL) string {
+	current, _ := strconv.Atoi(strings.TrimSpace(doc.Find("div.pg strong").First().Text()))
+	maxPage, href := current, ""
+	doc.Find("div.pg a[href]").Each(func(_ int, a *goquery.Selection) {
+		// 末页链接的文字形如 "... 12"，上一页/下一页链接没有数字。
+		num := forumPageNumRe.FindString(a.Text())
+		if num == "" {
+			return
+		}
+		page, _ := strconv.Atoi(num)
+		if page > maxPage {
+			maxPage = page
+			href, _ = a.Attr("href")
+		}
+	})
+	return resolveLink(base, href)
+}
+
+// isWatchedAuthor 判断作者是否在关注名单中，忽略大小写与全角/半角差异。
+func isWatchedAuthor(authors []string, author string) bool {
+	author = foldWidth(strings.TrimSpace(author))
+	if author == "" {
+		return false
+	}
+	for _, a := range authors {
+		if foldWidth(strings.TrimSpace(a)) == author {
+			return true
+		}
+	}
+	return false
+}
+
+// checkForumThread 检查一个关注的帖子：首次只记录基线；之后按名单提醒指定作者的新回复，
+// 或在回复数、最后回复时间变化时提醒一次。
+func (m *Monitor) checkForumThread(ctx context.Context, appCtx context.Context, run checkRun, c threadChecker) error {
+	t, err := c.fetchThread(ctx, m.httpClient)
+	if err != nil {
+		return err
+	}
+	if len(t.Posts) == 0 {
+		m.emitLog(appCtx, "WARN", "未找到"+c.Name()+"的回复")
+		return nil
+	}
+	name := c.Name()
+	last := t.Posts[len(t.Posts)-1]
+	marker := strconv.Itoa(t.Replies) + "|" + last.Time
+
+	posts := make([]latestItem, 0, len(t.Posts))
+	for _, p := range t.Posts {
+		posts = append(posts, latestItem{Key: p.PID, Title: t.Title, Link: p.Link})
+	}
+
+	m.mu.Lock()
+	st := m.sourceStates[name]
+	st.SeenKeys = append([]string(nil), st.SeenKeys...)
+	m.mu.Unlock()
+
+	if len(st.SeenKeys) == 0 && st.Marker == "" {
+		st.SeenKeys = itemKeys(posts)
+		st.Marker = marker
+		st.LastTitle = t.Title
+		st.LastLink = last.Link
+		m.setSourceState(name, st)
+		m.emitLog(appCtx, "INFO", "已获取"+name+"当前回复(基线): 回复 "+strconv.Itoa(t.Replies))
+		return nil
+	}
+
+	newPosts, updated := diffSeen(posts, st.SeenKeys)
+	if len(newPosts) == 0 && st.Marker == marker {
+		m.emitLog(appCtx, "INFO", name+"没有新回复")
+		return nil
+	}
+
+	authorOf := map[string]forumPost{}
+	for _, p := range t.Posts {
+		authorOf[p.PID] = p
+	}
+
+	var notify []latestItem
+	if len(c.cfg.Authors) > 0 {
+		for _, it := range newPosts {
+			p := authorOf[it.Key]
+			if !isWatchedAuthor(c.cfg.Authors, p.Author) {
+				continue
+			}
+			it.Title = t.Title + "（" + p.Author + " 回复于 " + p.Time + "）"
+			notify = append(notify, it)
+		}
+	} else {
+		notify = append(notify, latestItem{
+			Key:   last.PID,
+			Title: t.Title + "（回复 " + strconv.Itoa(t.Replies) + "，最后回复：" + last.Author + " " + last.Time + "）",
+			Link:  last.Link,
+		})
+	}
+
+	st.SeenKeys = updated
+	st.Marker = marker
+	st.LastTitle = t.Title
+	st.LastLink = last.Link
+	m.setSourceState(name, st)
+
+	if len(notify) == 0 {
+		m.emitLog(appCtx, "INFO", name+"有新回复，但不是关注的作者")
+		return nil
+	}
+	for _, it := range notify {
+		m.emitLog(appCtx, "INFO", "检测到"+name+"新回复: "+it.Title)
+		m.deliver(ctx, appCtx, run, c, it)
+	}
+	return nil
+}
+
+func validateForumThreads(threads []ForumThreadConfig) error {
+	for _, t := range threads {
+		if err := validateSourceURL(t.URL); err != nil {
+			return errors.New(t.Name + ": " + err.Error())
+		}
+		if forumThreadID(t.URL) == "" {
+			return errors.New(t.Name + ": 未从地址中解析到帖子 tid: " + t.URL)
+		}
+	}
+	return nil
+}
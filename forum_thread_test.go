@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testThreadURL = "https://bbs.example.com/forum.php?mod=viewthread&tid=555"
+
+// threadPages 按请求中的 page 参数返回帖子的各页，未带 page 时为第一页。
+type threadPages map[string]string
+
+func (p threadPages) RoundTrip(r *http.Request) (*http.Response, error) {
+	page := r.URL.Query().Get("page")
+	if page == "" {
+		page = "1"
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		Body:       io.NopCloser(strings.NewReader(p[page])),
+		Request:    r,
+	}, nil
+}
+
+// threadPost 生成帖子页中的一楼。
+func threadPost(pid int, author, postedAt string) string {
+	return fmt.Sprintf(`<div id="post_%d"><table><tr><td class="pls"><div class="authi"><a class="xw1">%s</a></div></td>`+
+		`<td><em id="authorposton%d">发表于 <span title="%s">不久前</span></em></td></tr></table></div>`, pid, author, pid, postedAt)
+}
+
+// threadPage 生成帖子的第 current 页，lastPage 大于 1 时带上分页。
+func threadPage(replies, current, lastPage int, posts ...string) string {
+	pager := ""
+	if lastPage > 1 {
+		pager = fmt.Sprintf(`<div class="pg"><strong>%d</strong><a href="forum.php?mod=viewthread&tid=555&page=%d">... %d</a><a class="nxt" href="#">下一页</a></div>`,
+			current, lastPage, lastPage)
+	}
+	return fmt.Sprintf(`<html><body><span id="thread_subject">补偿 公告</span>`+
+		`<div class="hm ptn"><span class="xi1">9999</span><span class="xi1">%d</span></div>%s<div id="postlist">%s</div></body></html>`,
+		replies, pager, strings.Join(posts, ""))
+}
+
+func TestFetchThreadFollowsLastPage(t *testing.T) {
+	pages := threadPages{
+		"1": threadPage(20, 1, 3, threadPost(1, "GM", "2026-10-01 10:00")),
+		"3": threadPage(22, 3, 3, threadPost(41, "玩家甲", "2026-10-16 09:00"), threadPost(42, "ＧＭ", "2026-10-16 10:00")),
+	}
+	c := threadChecker{cfg: ForumThreadConfig{Name: "补偿帖", URL: testThreadURL}}
+	th, err := c.fetchThread(context.Background(), &http.Client{Transport: pages})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Title != "补偿 公告" || th.Replies != 22 || len(th.Posts) != 2 {
+		t.Fatalf("thread = %+v", th)
+	}
+	want := forumPost{PID: "42", Author: "ＧＭ", Time: "2026-10-16 10:00",
+		Link: "https://bbs.example.com/forum.php?mod=redirect&goto=findpost&ptid=555&pid=42"}
+	if th.Posts[1] != want {
+		t.Errorf("最后一楼 = %+v, 期望 %+v", th.Posts[1], want)
+	}
+}
+
+func TestCheckForumThread(t *testing.T) {
+	base := []string{threadPost(1, "GM", "2026-10-01 10:00"), threadPost(2, "玩家甲", "2026-10-02 10:00")}
+	steps := []struct {
+		name  string
+		posts []string
+		// want 为这一步应提醒的标题
+		want []string
+	}{
+		{name: "首次检查只记录基线", posts: base},
+		{name: "没有变化", posts: base},
+		{
+			name:  "玩家回复",
+			posts: append(slices.Clone(base), threadPost(3, "玩家乙", "2026-10-16 09:00")),
+			want:  []string{"补偿 公告（回复 2，最后回复：玩家乙 2026-10-16 09:00）"},
+		},
+		{
+			name:  "关注的作者回复，名称为全角",
+			posts: append(slices.Clone(base), threadPost(3, "玩家乙", "2026-10-16 09:00"), threadPost(4, "ｇｍ", "2026-10-16 10:00")),
+			want:  []string{"补偿 公告（回复 3，最后回复：ｇｍ 2026-10-16 10:00）"},
+		},
+	}
+	authorSteps := [][]string{nil, nil, nil, {"补偿 公告（ｇｍ 回复于 2026-10-16 10:00）"}}
+
+	for _, authors := range [][]string{nil, {"GM"}} {
+		useTempConfigDir(t)
+		m := NewMonitor()
+		c := threadChecker{cfg: ForumThreadConfig{Name: "补偿帖", URL: testThreadURL, Authors: authors}}
+		m.httpClient = &http.Client{Transport: threadPages{}}
+
+		reported := 0
+		for i, step := range steps {
+			m.httpClient.Transport = threadPages{"1": threadPage(len(step.posts)-1, 1, 1, step.posts...)}
+			m.mu.Lock()
+			run := m.newCheckRunLocked(time.Now())
+			m.mu.Unlock()
+			if err := m.checkForumThread(context.Background(), nil, run, c); err != nil {
+				t.Fatalf("%s: %v", step.name, err)
+			}
+
+			history := m.History()
+			var got []string
+			for j := len(history) - reported - 1; j >= 0; j-- {
+				got = append(got, history[j].Title)
+			}
+			reported = len(history)
+
+			want := step.want
+			if len(authors) > 0 {
+				want = authorSteps[i]
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("authors=%v %s: 提醒 %v, 期望 %v", authors, step.name, got, want)
+			}
+		}
+	}
+}
@@ -7,11 +7,21 @@ import {
   WindowHide,
 } from "../wailsjs/runtime/runtime";
 import {
+  ClearDeadLetters,
+  DryRunRules,
+  ExportMaintenanceCalendar,
   GetAppInfo,
+  GetHistory,
+  GetMaintenanceEvents,
   GetSettings,
   GetStatus,
   QuitApp,
+  RequeueDeadLetters,
+  SaveSettings,
+  SendTestNotification,
+  StartBurstCheck,
   StartMonitoring,
+  StopBurstCheck,
   StopMonitoring,
 } from "../wailsjs/go/main/App";
 
@@ -20,12 +30,21 @@ document.querySelector("#app").innerHTML = `
         <h1 class="title">怀旧天龙公告检测</h1>
 
         <div class="input-box">
-            <input class="input" id="channelKey" type="text" autocomplete="off" placeholder="微信单点推送链接（例如：https://xizhi.qqoq.net/XZxxxx.send，可选）" />
+            <input class="input" id="channelKey" type="text" autocomplete="off" placeholder="推送链接或 Key（可选，自动识别息知、Server酱、PushPlus、Bark）" />
             <button class="btn" id="startBtn">开始监控</button>
             <button class="btn" id="stopBtn">结束监控</button>
             <button class="btn" id="minToTrayBtn" style="display:none;">最小化到托盘</button>
         </div>
 
+        <div class="toolbar">
+            <button class="btn" id="settingsBtn">设置</button>
+            <button class="btn" id="burstBtn">加速检查</button>
+            <button class="btn" id="historyBtn">历史记录</button>
+            <button class="btn" id="maintenanceBtn">维护日程</button>
+            <button class="btn" id="dryRunBtn">规则试运行</button>
+            <button class="btn" id="outboxBtn">失败推送</button>
+        </div>
+
         <div class="result" id="status">状态：加载中...</div>
 
         <textarea class="log" id="log" readonly spellcheck="false"></textarea>
@@ -33,8 +52,9 @@ document.querySelector("#app").innerHTML = `
         <div class="footer">
             <div class="footer-left">
                 <div>说明：自动检测公告/活动/论坛，发现更新会打开浏览器进入对应页面。</div>
-                <div>操作：点击【开始监控】启动；需要推送则填写微信单点推送链接/Key；点击【结束监控】停止。</div>
-                <button class="footer-btn" id="getPushLinkBtn" type="button">如何获取微信推送链接？</button>
+                <div>操作：点击【开始监控】启动；需要推送则填写息知、Server酱（SCT…）、PushPlus（token）或 Bark 的推送链接/Key，会自动识别；点击【结束监控】停止。</div>
+                <div>更多推送渠道、推送模板、规则与自定义来源在【设置】中配置。</div>
+                <button class="footer-btn" id="getPushLinkBtn" type="button">如何获取息知微信推送链接？</button>
                 <div>作者：<span id="author"></span>　版本：<span id="version"></span></div>
             </div>
             <div class="footer-right">
@@ -54,6 +74,74 @@ document.querySelector("#app").innerHTML = `
             </div>
         </div>
     </div>
+
+    <div class="modal-mask" id="burstMask" style="display:none;">
+        <div class="modal">
+            <div class="modal-title">加速检查</div>
+            <div class="modal-content">
+                在接下来一段时间内按固定间隔检查所有来源，适合维护结束或活动开始前后。
+                <div class="modal-row">
+                    每 <input class="modal-input" id="burstInterval" type="number" min="10" value="30" /> 秒，
+                    持续 <input class="modal-input" id="burstMinutes" type="number" min="1" value="30" /> 分钟
+                </div>
+                <div class="modal-message" id="burstMsg"></div>
+            </div>
+            <div class="modal-actions">
+                <button class="btn" id="burstStartBtn">开始加速</button>
+                <button class="btn" id="burstStopBtn">停止加速</button>
+                <button class="btn" id="burstCancelBtn">取消</button>
+            </div>
+        </div>
+    </div>
+
+    <div class="modal-mask" id="viewerMask" style="display:none;">
+        <div class="modal modal-wide">
+            <div class="modal-title" id="viewerTitle"></div>
+            <div class="modal-content">
+                <pre class="viewer-content" id="viewerContent"></pre>
+                <div class="modal-message" id="viewerMsg"></div>
+            </div>
+            <div class="modal-actions" id="viewerActions"></div>
+        </div>
+    </div>
+
+    <div class="modal-mask" id="dryRunMask" style="display:none;">
+        <div class="modal">
+            <div class="modal-title">规则试运行</div>
+            <div class="modal-content">
+                用示例条目检查已保存的规则与过滤规则会执行哪些动作，不会真正打开链接或推送。时间按北京时间，留空为当前时间。
+                <div class="modal-row">来源 <input class="modal-input modal-input-wide" id="dryRunSource" type="text" value="公告" /></div>
+                <div class="modal-row">标题 <input class="modal-input modal-input-wide" id="dryRunTitle" type="text" /></div>
+                <div class="modal-row">时间 <input class="modal-input modal-input-wide" id="dryRunTime" type="datetime-local" /></div>
+                <div class="modal-message" id="dryRunMsg"></div>
+            </div>
+            <div class="modal-actions">
+                <button class="btn" id="dryRunRunBtn">试运行</button>
+                <button class="btn" id="dryRunCloseBtn">关闭</button>
+            </div>
+        </div>
+    </div>
+
+    <div class="modal-mask" id="settingsMask" style="display:none;">
+        <div class="modal modal-wide">
+            <div class="modal-title">设置</div>
+            <div class="modal-content">
+                以 JSON 编辑全部设置：推送渠道（notifiers）、推送模板（pushTemplates）、过滤规则与规则（filterRules、rules）、
+                自定义来源、论坛版块、维护提醒与检查时段（scheduleProfile）等。主界面的推送链接/Key 单独保存。
+                <textarea class="settings-editor" id="settingsEditor" spellcheck="false"></textarea>
+                <div class="modal-row">
+                    测试推送渠道：<select class="modal-input" id="testNotifier"></select>
+                    <button class="btn" id="testNotifierBtn">发送测试</button>
+                </div>
+                <div class="modal-message" id="settingsMsg"></div>
+            </div>
+            <div class="modal-actions">
+                <button class="btn" id="settingsSaveBtn">保存</button>
+                <button class="btn" id="settingsReloadBtn">重新载入</button>
+                <button class="btn" id="settingsCloseBtn">关闭</button>
+            </div>
+        </div>
+    </div>
 `;
 
 const channelKeyEl = document.getElementById("channelKey");
@@ -71,6 +159,44 @@ const closePromptMinBtn = document.getElementById("closePromptMinBtn");
 const closePromptExitBtn = document.getElementById("closePromptExitBtn");
 const closePromptCancelBtn = document.getElementById("closePromptCancelBtn");
 
+const outboxBtn = document.getElementById("outboxBtn");
+
+const burstBtn = document.getElementById("burstBtn");
+const burstMask = document.getElementById("burstMask");
+const burstIntervalEl = document.getElementById("burstInterval");
+const burstMinutesEl = document.getElementById("burstMinutes");
+const burstStartBtn = document.getElementById("burstStartBtn");
+const burstStopBtn = document.getElementById("burstStopBtn");
+const burstCancelBtn = document.getElementById("burstCancelBtn");
+const burstMsgEl = document.getElementById("burstMsg");
+const historyBtn = document.getElementById("historyBtn");
+const maintenanceBtn = document.getElementById("maintenanceBtn");
+
+const dryRunBtn = document.getElementById("dryRunBtn");
+const dryRunMask = document.getElementById("dryRunMask");
+const dryRunSourceEl = document.getElementById("dryRunSource");
+const dryRunTitleEl = document.getElementById("dryRunTitle");
+const dryRunTimeEl = document.getElementById("dryRunTime");
+const dryRunMsgEl = document.getElementById("dryRunMsg");
+const dryRunRunBtn = document.getElementById("dryRunRunBtn");
+const dryRunCloseBtn = document.getElementById("dryRunCloseBtn");
+
+const viewerMask = document.getElementById("viewerMask");
+const viewerTitleEl = document.getElementById("viewerTitle");
+const viewerContentEl = document.getElementById("viewerContent");
+const viewerMsgEl = document.getElementById("viewerMsg");
+const viewerActionsEl = document.getElementById("viewerActions");
+
+const settingsBtn = document.getElementById("settingsBtn");
+const settingsMask = document.getElementById("settingsMask");
+const settingsEditorEl = document.getElementById("settingsEditor");
+const testNotifierEl = document.getElementById("testNotifier");
+const testNotifierBtn = document.getElementById("testNotifierBtn");
+const settingsSaveBtn = document.getElementById("settingsSaveBtn");
+const settingsReloadBtn = document.getElementById("settingsReloadBtn");
+const settingsCloseBtn = document.getElementById("settingsCloseBtn");
+const settingsMsgEl = document.getElementById("settingsMsg");
+
 const MAX_LOG_LINES = 2000;
 const logLines = [];
 
@@ -112,10 +238,43 @@ function appendLog(line) {
   logEl.scrollTop = logEl.scrollHeight;
 }
 
+let audioCtx = null;
+
+// 内置提示音：两声短促的蜂鸣
+function beep() {
+  audioCtx = audioCtx || new (window.AudioContext || window.webkitAudioContext)();
+  const start = audioCtx.currentTime;
+  [0, 0.25].forEach((offset) => {
+    const osc = audioCtx.createOscillator();
+    const gain = audioCtx.createGain();
+    osc.frequency.value = 880;
+    gain.gain.setValueAtTime(0.2, start + offset);
+    gain.gain.exponentialRampToValueAtTime(0.001, start + offset + 0.2);
+    osc.connect(gain).connect(audioCtx.destination);
+    osc.start(start + offset);
+    osc.stop(start + offset + 0.2);
+  });
+}
+
+// 规则中的声音动作：音频地址直接播放，其他取值播放内置提示音
+function playSound(sound) {
+  const src = (sound || "").trim();
+  if (/^(https?:|data:|\/)/i.test(src)) {
+    new Audio(src).play().catch((e) => {
+      appendLog(`播放声音失败：${e}`);
+      beep();
+    });
+    return;
+  }
+  beep();
+}
+
 function setButtons(running) {
   startBtn.disabled = !!running;
   stopBtn.disabled = !running;
 
+  if (burstBtn) burstBtn.disabled = !running;
+
   if (minToTrayBtn) {
     minToTrayBtn.disabled = !running;
     minToTrayBtn.style.display = running ? "" : "none";
@@ -139,7 +298,10 @@ async function refreshStatus() {
     const announce = s.lastTitle ? `，公告：${s.lastTitle}` : "";
     const act = s.lastActivityTitle ? `，活动：${s.lastActivityTitle}` : "";
     const forum = s.lastForumTitle ? `，论坛：${s.lastForumTitle}` : "";
-    statusEl.innerText = `状态：${s.running ? "运行中" : "已停止"}${checked}${announce}${act}${forum}`;
+    const dead = s.outboxDead ? `，失败推送：${s.outboxDead} 条` : "";
+    const burstUntil = s.burstUntil ? formatLocalTime(new Date(s.burstUntil)) : "";
+    const burst = burstUntil ? `，加速检查至 ${burstUntil}` : "";
+    statusEl.innerText = `状态：${s.running ? "运行中" : "已停止"}${checked}${announce}${act}${forum}${dead}${burst}`;
   } catch (e) {
     statusEl.innerText = "状态：获取失败";
     appendLog(String(e));
@@ -203,10 +365,291 @@ closePromptCancelBtn?.addEventListener("click", () => {
   hideClosePrompt();
 });
 
+// 弹窗遮住了日志，弹窗内的操作结果同时显示在弹窗中
+function modalMessage(el, text) {
+  if (el) el.innerText = text;
+  appendLog(text);
+}
+
+function closeViewer() {
+  viewerMask.style.display = "none";
+}
+
+// openViewer 在弹窗中显示只读文本，actions 为附加的按钮 { label, onClick }
+function openViewer(title, text, actions = []) {
+  viewerTitleEl.innerText = title;
+  viewerContentEl.innerText = text;
+  viewerMsgEl.innerText = "";
+  viewerActionsEl.innerHTML = "";
+  [...actions, { label: "关闭", onClick: closeViewer }].forEach((a) => {
+    const btn = document.createElement("button");
+    btn.className = "btn";
+    btn.textContent = a.label;
+    btn.addEventListener("click", async () => {
+      try {
+        await a.onClick();
+      } catch (e) {
+        modalMessage(viewerMsgEl, String(e));
+      }
+    });
+    viewerActionsEl.appendChild(btn);
+  });
+  viewerMask.style.display = "";
+}
+
+async function showOutbox() {
+  const s = await GetStatus();
+  const lines = [
+    `待重试：${s.outboxPending} 条`,
+    `超过重试上限（${s.outboxRetryLimit} 次）：${s.outboxDead} 条`,
+  ];
+  if (s.lastDeadLetter) lines.push(`最近一条：${s.lastDeadLetter}`);
+  openViewer("失败推送", lines.join("\n"), [
+    {
+      label: "重新推送",
+      onClick: async () => {
+        const n = await RequeueDeadLetters();
+        await showOutbox();
+        await refreshStatus();
+        modalMessage(viewerMsgEl, `已放回重试队列：${n} 条`);
+      },
+    },
+    {
+      label: "清空",
+      onClick: async () => {
+        await ClearDeadLetters();
+        await showOutbox();
+        await refreshStatus();
+        modalMessage(viewerMsgEl, "已清空超过重试上限的推送");
+      },
+    },
+  ]);
+}
+
+outboxBtn?.addEventListener("click", async () => {
+  try {
+    await showOutbox();
+  } catch (e) {
+    appendLog(String(e));
+  }
+});
+
+function formatHistoryEntry(e) {
+  const time = formatLocalTime(new Date(e.detectedAt));
+  const author = e.author ? `（${e.author}）` : "";
+  let line = `${time} [${e.source}] ${e.title}${author}`;
+  if (!e.notified) line += e.note ? ` —— 未提醒：${e.note}` : " —— 未提醒";
+  if (e.link) line += `\n    ${e.link}`;
+  return line;
+}
+
+historyBtn?.addEventListener("click", async () => {
+  try {
+    const entries = (await GetHistory()) || [];
+    const text = entries.length ? entries.map(formatHistoryEntry).join("\n") : "暂无记录";
+    openViewer(`历史记录（${entries.length} 条，包括未提醒的条目）`, text);
+  } catch (e) {
+    appendLog(String(e));
+  }
+});
+
+function formatMaintenanceEvent(e) {
+  const range = `${formatLocalTime(new Date(e.start))} 至 ${formatLocalTime(new Date(e.end))}`;
+  let line = `${range} ${e.title}${e.reminded ? "（已提醒）" : ""}`;
+  if (e.link) line += `\n    ${e.link}`;
+  return line;
+}
+
+maintenanceBtn?.addEventListener("click", async () => {
+  try {
+    const events = (await GetMaintenanceEvents()) || [];
+    const text = events.length ? events.map(formatMaintenanceEvent).join("\n") : "暂未从公告中解析到维护时间";
+    openViewer("维护日程", text, [
+      {
+        label: "导出日历",
+        onClick: async () => {
+          const path = await ExportMaintenanceCalendar();
+          modalMessage(viewerMsgEl, `已导出：${path}`);
+        },
+      },
+    ]);
+  } catch (e) {
+    appendLog(String(e));
+  }
+});
+
+burstBtn?.addEventListener("click", () => {
+  burstMsgEl.innerText = "";
+  burstMask.style.display = "";
+});
+
+burstStartBtn?.addEventListener("click", async () => {
+  const interval = parseInt(burstIntervalEl.value, 10) || 0;
+  const minutes = parseInt(burstMinutesEl.value, 10) || 0;
+  try {
+    await StartBurstCheck(interval, minutes);
+    burstMask.style.display = "none";
+    await refreshStatus();
+  } catch (e) {
+    modalMessage(burstMsgEl, `加速检查失败：${e}`);
+  }
+});
+
+burstStopBtn?.addEventListener("click", async () => {
+  try {
+    await StopBurstCheck();
+    burstMask.style.display = "none";
+    await refreshStatus();
+  } catch (e) {
+    modalMessage(burstMsgEl, String(e));
+  }
+});
+
+burstCancelBtn?.addEventListener("click", () => {
+  burstMask.style.display = "none";
+});
+
+dryRunBtn?.addEventListener("click", () => {
+  dryRunMsgEl.innerText = "";
+  dryRunMask.style.display = "";
+});
+
+dryRunRunBtn?.addEventListener("click", async () => {
+  // datetime-local 没有时区，按北京时间补成 RFC3339
+  const local = (dryRunTimeEl.value || "").trim();
+  const time = local ? `${local.length === 16 ? `${local}:00` : local}+08:00` : "";
+  try {
+    const r = await DryRunRules({
+      source: (dryRunSourceEl.value || "").trim(),
+      title: dryRunTitleEl.value || "",
+      time,
+    });
+    const lines = [];
+    if (r.matchedRules?.length) lines.push(`命中规则：${r.matchedRules.join("、")}`);
+    if (r.usedFilter) lines.push("未命中规则，按过滤规则处理");
+    if (r.keywords?.length) lines.push(`命中关键词：${r.keywords.join("、")}`);
+    lines.push(`动作：${(r.summary || []).join(" → ")}`);
+    modalMessage(dryRunMsgEl, lines.join("\n"));
+  } catch (e) {
+    modalMessage(dryRunMsgEl, `试运行失败：${e}`);
+  }
+});
+
+dryRunCloseBtn?.addEventListener("click", () => {
+  dryRunMask.style.display = "none";
+});
+
+// 设置中的 channelKey 由主界面输入框维护，编辑器中只显示其余部分
+function showSettingsEditor(s) {
+  const { channelKey, ...rest } = s || {};
+  settingsEditorEl.value = JSON.stringify(rest, null, 2);
+  refreshTestNotifiers();
+}
+
+function parseSettingsEditor() {
+  const s = JSON.parse(settingsEditorEl.value || "{}");
+  s.channelKey = (channelKeyEl.value || "").trim();
+  return s;
+}
+
+// 测试渠道列表取自编辑器中尚未保存的内容，便于保存前检查配置与模板
+function testNotifierConfigs() {
+  const out = [];
+  const key = (channelKeyEl.value || "").trim();
+  if (key) {
+    out.push({ label: "主界面推送链接/Key", cfg: { type: "auto", enabled: true, target: key } });
+  }
+  let notifiers = [];
+  try {
+    notifiers = parseSettingsEditor().notifiers || [];
+  } catch (e) {
+    notifiers = [];
+  }
+  notifiers.forEach((n) => {
+    out.push({ label: n.name || n.type || "未命名渠道", cfg: n });
+  });
+  return out;
+}
+
+function refreshTestNotifiers() {
+  testNotifierEl.innerHTML = "";
+  testNotifierConfigs().forEach((c, i) => {
+    const opt = document.createElement("option");
+    opt.value = String(i);
+    opt.textContent = c.label;
+    testNotifierEl.appendChild(opt);
+  });
+}
+
+async function loadSettingsEditor() {
+  try {
+    showSettingsEditor(await GetSettings());
+  } catch (e) {
+    modalMessage(settingsMsgEl, `读取设置失败：${e}`);
+  }
+}
+
+settingsBtn?.addEventListener("click", async () => {
+  settingsMsgEl.innerText = "";
+  await loadSettingsEditor();
+  settingsMask.style.display = "";
+});
+
+settingsEditorEl?.addEventListener("change", () => {
+  refreshTestNotifiers();
+});
+
+testNotifierBtn?.addEventListener("click", async () => {
+  const picked = testNotifierConfigs()[parseInt(testNotifierEl.value, 10)];
+  if (!picked) {
+    modalMessage(settingsMsgEl, "没有可测试的推送渠道");
+    return;
+  }
+  try {
+    await SendTestNotification(picked.cfg);
+    modalMessage(settingsMsgEl, `${picked.label} 测试推送已发送`);
+  } catch (e) {
+    modalMessage(settingsMsgEl, `${picked.label} 测试推送失败：${e}`);
+  }
+});
+
+settingsSaveBtn?.addEventListener("click", async () => {
+  let s;
+  try {
+    s = parseSettingsEditor();
+  } catch (e) {
+    modalMessage(settingsMsgEl, `设置不是有效的 JSON：${e}`);
+    return;
+  }
+  try {
+    await SaveSettings(s);
+    await loadSettingsEditor();
+    modalMessage(settingsMsgEl, "设置已保存");
+  } catch (e) {
+    modalMessage(settingsMsgEl, `保存设置失败：${e}`);
+  }
+});
+
+settingsReloadBtn?.addEventListener("click", () => {
+  loadSettingsEditor();
+});
+
+settingsCloseBtn?.addEventListener("click", () => {
+  settingsMask.style.display = "none";
+});
+
 EventsOn("log", (line) => {
   appendLog(line);
 });
 
+EventsOn("sound", (sound) => {
+  try {
+    playSound(sound);
+  } catch (e) {
+    appendLog(String(e));
+  }
+});
+
 // 后端拦截关闭按钮时触发
 EventsOn("app:close-requested", () => {
   showClosePrompt();
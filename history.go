@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	historyFileName = "history.json"
+
+	// historyMaxEntries 为保留的历史条数上限，超出时丢弃最旧的。
+	historyMaxEntries = 500
+)
+
+// HistoryEntry 为一条检测到的新条目，无论是否提醒都会记录，便于事后查看。
+// Content 为详情页正文全文，推送中只带摘要。
+type HistoryEntry struct {
+	Source     string    `json:"source"`
+	Title      string    `json:"title"`
+	Link       string    `json:"link"`
+	Author     string    `json:"author,omitempty"`
+	Content    string    `json:"content,omitempty"`
+	Notified   bool      `json:"notified"`
+	Note       string    `json:"note,omitempty"`
+	DetectedAt time.Time `json:"detectedAt"`
+}
+
+func historyFilePath() (string, error) {
+	path, err := settingsFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), historyFileName), nil
+}
+
+func loadHistory() ([]HistoryEntry, error) {
+	path, err := historyFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var entries []HistoryEntry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func saveHistory(entries []HistoryEntry) error {
+	path, err := historyFilePath()
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// recordHistory 追加一条历史并写入文件。
+func (m *Monitor) recordHistory(e HistoryEntry) {
+	m.persistMu.Lock()
+	defer m.persistMu.Unlock()
+	m.mu.Lock()
+	m.history = append(m.history, e)
+	if len(m.history) > historyMaxEntries {
+		m.history = m.history[len(m.history)-historyMaxEntries:]
+	}
+	entries := append([]HistoryEntry(nil), m.history...)
+	m.mu.Unlock()
+	_ = saveHistory(entries)
+}
+
+// History 返回检测历史，最新的在前。
+func (m *Monitor) History() []HistoryEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]HistoryEntry, 0, len(m.history))
+	for i := len(m.history) - 1; i >= 0; i-- {
+		out = append(out, m.history[i])
+	}
+	return out
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maintenanceFileName = "maintenance.json"
+	maintenanceICSName  = "maintenance.ics"
+
+	// defaultReminderMinutes 为维护结束前多少分钟发送提醒。
+	defaultReminderMinutes = 10
+	// maintenanceKeep 为维护结束后保留事件的时长。
+	maintenanceKeep = 30 * 24 * time.Hour
+	// maintenanceTick 为检查是否需要发送提醒的间隔。
+	maintenanceTick = 30 * time.Second
+)
+
+// MaintenanceConfig 控制维护提醒：ReminderMinutes 为维护结束前多少分钟提醒，0 表示默认值。
+type MaintenanceConfig struct {
+	ReminderMinutes int `json:"reminderMinutes"`
+}
+
+func (c MaintenanceConfig) withDefaults() MaintenanceConfig {
+	if c.ReminderMinutes <= 0 {
+		c.ReminderMinutes = defaultReminderMinutes
+	}
+	return c
+}
+
+func validateMaintenance(c MaintenanceConfig) error {
+	if c.ReminderMinutes < 0 || c.ReminderMinutes > 24*60 {
+		return errors.New("维护提醒提前分钟数应为 0-1440")
+	}
+	return nil
+}
+
+// MaintenanceEvent 为从维护公告中解析出的一个维护时间段，Key 为所属公告的 key。
+// 同一公告的事件在公告修改后整体替换，ID 按公告与序号生成，日历中的事件随之更新而不是重复。
+type MaintenanceEvent struct {
+	ID       string    `json:"id"`
+	Key      string    `json:"key"`
+	Title    string    `json:"title"`
+	Link     string    `json:"link"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Reminded bool      `json:"reminded"`
+}
+
+// maintenanceSource 为维护即将结束的提醒，只推送，不打开链接。
+type maintenanceSource struct{}
+
+func (maintenanceSource) Name() string     { return "维护提醒" }
+func (maintenanceSource) PushHead() string { return "维护即将结束" }
+
+// maintenanceRangeRe 匹配“10月16日（周四）8:00-11:00”“10月16日 23:00 至 10月17日 2:00”等写法。
+var maintenanceRangeRe = regexp.MustCompile(`(\d{1,2})月(\d{1,2})日[^\d\n]{0,12}?(\d{1,2})[:：](\d{2})\s*[-－—–~～至到]+\s*(?:(\d{1,2})月(\d{1,2})日)?[^\d\n]{0,6}?(\d{1,2})[:：](\d{2})`)
+
+// beijingTime 为公告中时间所用的时区。
+var beijingTime = time.FixedZone("CST", 8*3600)
+
+// parseMaintenanceWindows 从公告文字中解析维护时间段（北京时间）。公告中不写年份，取离 now 最近的年份。
+func parseMaintenanceWindows(text string, now time.Time) [][2]time.Time {
+	now = now.In(beijingTime)
+	var out [][2]time.Time
+	for _, m := range maintenanceRangeRe.FindAllStringSubmatch(text, -1) {
+		n := make([]int, len(m))
+		for i := 1; i < len(m); i++ {
+			n[i], _ = strconv.Atoi(m[i])
+		}
+		if n[1] < 1 || n[1] > 12 || n[2] < 1 || n[2] > 31 || n[3] > 24 || n[4] > 59 || n[7] > 24 || n[8] > 59 {
+			continue
+		}
+		start := nearestDate(now, n[1], n[2], n[3], n[4])
+		var end time.Time
+		if m[5] != "" {
+			end = nearestDate(start, n[5], n[6], n[7], n[8])
+		} else {
+			end = time.Date(start.Year(), start.Month(), start.Day(), n[7], n[8], 0, 0, now.Location())
+		}
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+		out = append(out, [2]time.Time{start, end})
+	}
+	return out
+}
+
+// nearestDate 返回离 ref 最近的那一年的该月日时分。
+func nearestDate(ref time.Time, month int, day int, hour int, minute int) time.Time {
+	best := time.Time{}
+	for _, y := range []int{ref.Year() - 1, ref.Year(), ref.Year() + 1} {
+		t := time.Date(y, time.Month(month), day, hour, minute, 0, 0, ref.Location())
+		if best.IsZero() || t.Sub(ref).Abs() < best.Sub(ref).Abs() {
+			best = t
+		}
+	}
+	return best
+}
+
+func maintenanceFilePath(name string) (string, error) {
+	path, err := settingsFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), name), nil
+}
+
+func loadMaintenance() ([]MaintenanceEvent, error) {
+	path, err := maintenanceFilePath(maintenanceFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var events []MaintenanceEvent
+	if err := json.Unmarshal(b, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// saveMaintenance 保存维护事件，并同时重新生成 .ics 日历文件。
+func saveMaintenance(events []MaintenanceEvent) error {
+	path, err := maintenanceFilePath(maintenanceFileName)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(events, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	_, err = writeMaintenanceICS(events)
+	return err
+}
+
+// writeMaintenanceICS 生成维护日历文件并返回其路径。
+func writeMaintenanceICS(events []MaintenanceEvent) (string, error) {
+	path, err := maintenanceFilePath(maintenanceICSName)
+	if err != nil {
+		return "", err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(buildICS(events, time.Now())), 0o644); err != nil {
+		return "", err
+	}
+	return path, os.Rename(tmp, path)
+}
+
+// buildICS 按 RFC 5545 生成日历内容，时间统一使用 UTC。
+func buildICS(events []MaintenanceEvent, now time.Time) string {
+	const layout = "20060102T150405Z"
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICSLine(s))
+		b.WriteString("\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//" + AppName + "//Maintenance//CN")
+	line("CALSCALE:GREGORIAN")
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.ID + "@tlbb-notice")
+		line("DTSTAMP:" + now.UTC().Format(layout))
+		line("DTSTART:" + e.Start.UTC().Format(layout))
+		line("DTEND:" + e.End.UTC().Format(layout))
+		line("SUMMARY:" + escapeICSText(e.Title))
+		if e.Link != "" {
+			line("URL:" + e.Link)
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+func escapeICSText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// foldICSLine 把超过 75 字节的行折行，不拆开多字节字符。
+func foldICSLine(s string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > 75 {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	return b.String()
+}
+
+func maintenanceEventID(key string, n int) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8]) + "-" + strconv.Itoa(n)
+}
+
+// recordMaintenance 从公告的标题与正文中解析维护时间段并保存，只解析含“维护”的公告。
+// 新公告与正文修改后的公告都会调用：同一公告之前记录的时间段整体替换，结束时间未变的保留提醒状态。
+// 其他公告已记录的相同时间段不再重复记录。
+func (m *Monitor) recordMaintenance(appCtx context.Context, item latestItem, now time.Time) {
+	var windows [][2]time.Time
+	if text := item.Title + "\n" + item.Body; strings.Contains(text, "维护") {
+		windows = parseMaintenanceWindows(text, now)
+	}
+
+	m.persistMu.Lock()
+	defer m.persistMu.Unlock()
+	m.mu.Lock()
+	prev := map[string]MaintenanceEvent{}
+	var keep []MaintenanceEvent
+	others := map[[2]time.Time]bool{}
+	for _, e := range m.maintenance {
+		if e.Key == item.Key {
+			prev[e.ID] = e
+			continue
+		}
+		keep = append(keep, e)
+		others[[2]time.Time{e.Start.UTC(), e.End.UTC()}] = true
+	}
+	if len(windows) == 0 && len(prev) == 0 {
+		m.mu.Unlock()
+		return
+	}
+
+	var fresh []MaintenanceEvent
+	changed := false
+	for _, w := range windows {
+		if others[[2]time.Time{w[0].UTC(), w[1].UTC()}] {
+			continue
+		}
+		e := MaintenanceEvent{
+			ID:    maintenanceEventID(item.Key, len(fresh)+1),
+			Key:   item.Key,
+			Title: item.Title,
+			Link:  item.Link,
+			Start: w[0],
+			End:   w[1],
+		}
+		old, ok := prev[e.ID]
+		if ok && old.Start.Equal(e.Start) && old.End.Equal(e.End) {
+			e.Reminded = old.Reminded
+		} else {
+			changed = true
+			m.emitLog(appCtx, "INFO", "已记录维护时间: "+w[0].Format("01-02 15:04")+" - "+w[1].Format("01-02 15:04"))
+		}
+		fresh = append(fresh, e)
+	}
+	if len(fresh) != len(prev) {
+		changed = true
+	}
+	m.maintenance = append(keep, fresh...)
+	m.pruneMaintenanceLocked(now)
+	events := append([]MaintenanceEvent(nil), m.maintenance...)
+	m.mu.Unlock()
+
+	if changed {
+		if err := saveMaintenance(events); err != nil {
+			m.emitLog(appCtx, "WARN", "保存维护日历失败: "+err.Error())
+		}
+	}
+}
+
+// pruneMaintenanceLocked 按开始时间排序并丢弃已结束较久的事件，调用方需持有 m.mu。
+func (m *Monitor) pruneMaintenanceLocked(now time.Time) {
+	var keep []MaintenanceEvent
+	for _, e := range m.maintenance {
+		if now.Sub(e.End) < maintenanceKeep {
+			keep = append(keep, e)
+		}
+	}
+	sort.Slice(keep, func(i, j int) bool { return keep[i].Start.Before(keep[j].Start) })
+	m.maintenance = keep
+}
+
+// runMaintenanceReminders 在监控运行期间定时检查，维护结束前发送一次提醒。
+// 提醒只推送，不经过规则，也不会打开浏览器。
+func (m *Monitor) runMaintenanceReminders(ctx context.Context, appCtx context.Context) {
+	t := time.NewTicker(maintenanceTick)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		now := time.Now()
+		m.persistMu.Lock()
+		m.mu.Lock()
+		lead := time.Duration(m.maintenanceCfg.withDefaults().ReminderMinutes) * time.Minute
+		var due []MaintenanceEvent
+		for i := range m.maintenance {
+			e := &m.maintenance[i]
+			if !e.Reminded && !now.Before(e.End.Add(-lead)) && now.Before(e.End) {
+				e.Reminded = true
+				due = append(due, *e)
+			}
+		}
+		run := m.newCheckRunLocked(now)
+		events := append([]MaintenanceEvent(nil), m.maintenance...)
+		m.mu.Unlock()
+
+		if len(due) > 0 {
+			_ = saveMaintenance(events)
+		}
+		m.persistMu.Unlock()
+		if len(due) == 0 {
+			continue
+		}
+		for _, e := range due {
+			item := latestItem{
+				Key:   e.ID,
+				Title: e.Title + "（预计 " + e.End.Format("15:04") + " 结束）",
+				Link:  e.Link,
+			}
+			m.emitLog(appCtx, "INFO", "维护即将结束: "+item.Title)
+			m.pushItem(ctx, appCtx, run, run.notifiers, maintenanceSource{}, item, nil)
+		}
+	}
+}
+
+// MaintenanceEvents 返回已记录的维护时间段，按开始时间排序。
+func (m *Monitor) MaintenanceEvents() []MaintenanceEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MaintenanceEvent(nil), m.maintenance...)
+}
+
+// ExportMaintenanceICS 重新生成维护日历文件并返回路径，可导入系统日历。
+func (m *Monitor) ExportMaintenanceICS() (string, error) {
+	return writeMaintenanceICS(m.MaintenanceEvents())
+}
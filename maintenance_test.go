@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordMaintenanceReplacesEventsOnEdit(t *testing.T) {
+	useTempConfigDir(t)
+
+	m := NewMonitor()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, beijingTime)
+	item := latestItem{
+		Key:   "http://tlhj.changyou.com/news/100.shtml",
+		Title: "10月16日停服维护公告",
+		Link:  "http://tlhj.changyou.com/news/100.shtml",
+		Body:  "维护时间：10月16日（周四）8:00-11:00",
+	}
+	m.recordMaintenance(nil, item, now)
+	events := m.MaintenanceEvents()
+	if len(events) != 1 {
+		t.Fatalf("events = %+v", events)
+	}
+	id := events[0].ID
+	m.maintenance[0].Reminded = true
+
+	// 同一时间段再次解析不改变提醒状态。
+	m.recordMaintenance(nil, item, now)
+	if events = m.MaintenanceEvents(); len(events) != 1 || !events[0].Reminded {
+		t.Fatalf("重复解析后 events = %+v", events)
+	}
+
+	// 公告修改为延长维护：替换原事件，结束时间更新并重新提醒。
+	item.Body = "维护时间延长至：10月16日（周四）8:00-13:30"
+	m.recordMaintenance(nil, item, now)
+	events = m.MaintenanceEvents()
+	if len(events) != 1 {
+		t.Fatalf("修改后 events = %+v", events)
+	}
+	e := events[0]
+	if e.ID != id || e.Reminded || e.End.In(beijingTime).Format("15:04") != "13:30" {
+		t.Fatalf("修改后 event = %+v", e)
+	}
+
+	// 其他公告提到相同的时间段时不重复记录。
+	other := latestItem{Key: "http://tlhj.changyou.com/news/101.shtml", Title: "维护预告", Body: item.Body}
+	m.recordMaintenance(nil, other, now)
+	if events = m.MaintenanceEvents(); len(events) != 1 {
+		t.Fatalf("其他公告后 events = %+v", events)
+	}
+}
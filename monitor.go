@@ -5,10 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"io"
 	"math/rand"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -20,11 +21,9 @@ import (
 const (
 	announceListURL = "http://tlhj.changyou.com/tlhj/newslist/announce/announce.shtml"
 	activityJSONURL = "https://event.changyou.com/cycms/tlhj/banner/main1.json"
-	forumListURL    = "https://bbs.tlhj.changyou.com/forum.php?mod=forumdisplay&fid=2"
-	minIntervalSec  = 300
-	maxIntervalSec  = 600
-
-	xizhiDefaultHost = "xizhi.qqoq.net"
+	// 未配置检查间隔时，每个来源每 5-10 分钟检查一次。
+	minIntervalSec = 300
+	maxIntervalSec = 600
 )
 
 type MonitorStatus struct {
@@ -35,17 +34,38 @@ type MonitorStatus struct {
 	LastForumTitle    string `json:"lastForumTitle"`
 	LastForumLink     string `json:"lastForumLink"`
 	LastChecked       string `json:"lastChecked"`
+
+	// 失败推送的重试队列与死信
+	OutboxPending    int    `json:"outboxPending"`
+	OutboxDead       int    `json:"outboxDead"`
+	OutboxRetryLimit int    `json:"outboxRetryLimit"`
+	LastDeadLetter   string `json:"lastDeadLetter"`
+
+	Sources []SourceStatus `json:"sources"`
+
+	// BurstUntil 为加速检查的结束时间，未开启时为空。
+	BurstUntil string `json:"burstUntil"`
 }
 
 type latestItem struct {
 	Key   string
 	Title string
 	Link  string
+	// Author 为发帖人，仅论坛帖子会解析。
+	Author string
+	// Body 为详情页正文全文，Summary 为推送用的截断摘要，目前仅公告会读取。
+	Body    string
+	Summary string
 }
 
-type checker interface {
+// pushSource 为推送消息的来源：Name 用作模板、规则与历史的索引，PushHead 为默认推送标题。
+type pushSource interface {
 	Name() string
 	PushHead() string
+}
+
+type checker interface {
+	pushSource
 	FetchLatest(ctx context.Context, client *http.Client) (latestItem, error)
 }
 
@@ -54,56 +74,56 @@ type announcementChecker struct{}
 func (announcementChecker) Name() string     { return "公告" }
 func (announcementChecker) PushHead() string { return "天龙发公告了" }
 
-func (announcementChecker) FetchLatest(ctx context.Context, client *http.Client) (latestItem, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, announceListURL, nil)
-	if err != nil {
-		return latestItem{}, err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
+func (c announcementChecker) FetchLatest(ctx context.Context, client *http.Client) (latestItem, error) {
+	all, err := c.FetchAll(ctx, client)
+	if err != nil || len(all) == 0 {
 		return latestItem{}, err
 	}
-	defer resp.Body.Close()
+	return all[0], nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// FetchAll 返回公告列表中的全部公告，顺序与页面一致（最新的在前）。
+func (announcementChecker) FetchAll(ctx context.Context, client *http.Client) ([]latestItem, error) {
+	body, err := fetchPage(ctx, client, announceListURL)
 	if err != nil {
-		return latestItem{}, err
+		return nil, err
 	}
 
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
-		return latestItem{}, err
+		return nil, err
 	}
 
+	base, _ := url.Parse(announceListURL)
+
+	var out []latestItem
+	seen := map[string]struct{}{}
 	// 公告标题选择器（与原 JS 保持一致）
-	sel := doc.Find(".news_list_sc .news_list li a .news_txt h6.textcont").First()
-	if sel.Length() == 0 {
-		return latestItem{}, nil
-	}
-
-	title := strings.TrimSpace(sel.Text())
-	link := ""
-
-	// 从 h6 往上找 a，取 href
-	if a := sel.ParentsFiltered("a").First(); a.Length() > 0 {
-		if href, ok := a.Attr("href"); ok {
-			href = strings.TrimSpace(href)
-			if href != "" {
-				base, baseErr := url.Parse(announceListURL)
-				ref, refErr := url.Parse(href)
-				if baseErr == nil && refErr == nil {
-					link = base.ResolveReference(ref).String()
-				}
-			}
+	doc.Find(".news_list_sc .news_list li a .news_txt h6.textcont").Each(func(_ int, sel *goquery.Selection) {
+		title := strings.TrimSpace(sel.Text())
+		link := ""
+
+		// 从 h6 往上找 a，取 href
+		if a := sel.ParentsFiltered("a").First(); a.Length() > 0 {
+			href, _ := a.Attr("href")
+			link = resolveLink(base, href)
 		}
-	}
 
-	key := strings.TrimSpace(link)
-	if key == "" {
-		key = strings.TrimSpace(title)
-	}
-	return latestItem{Key: key, Title: title, Link: link}, nil
+		key := strings.TrimSpace(link)
+		if key == "" {
+			key = strings.TrimSpace(title)
+		}
+		if key == "" {
+			return
+		}
+		// 置顶公告可能在列表中重复出现，只保留第一次。
+		if _, dup := seen[key]; dup {
+			return
+		}
+		seen[key] = struct{}{}
+		out = append(out, latestItem{Key: key, Title: title, Link: link})
+	})
+	return out, nil
 }
 
 type activityChecker struct{}
@@ -111,67 +131,26 @@ type activityChecker struct{}
 func (activityChecker) Name() string     { return "活动" }
 func (activityChecker) PushHead() string { return "天龙有新活动了" }
 
-func (activityChecker) FetchLatest(ctx context.Context, client *http.Client) (latestItem, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, activityJSONURL, nil)
-	if err != nil {
-		return latestItem{}, err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
+func (c activityChecker) FetchLatest(ctx context.Context, client *http.Client) (latestItem, error) {
+	all, err := c.FetchAll(ctx, client)
+	if err != nil || len(all) == 0 {
 		return latestItem{}, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		b, _ := io.ReadAll(resp.Body)
-		return latestItem{}, errors.New("HTTP " + resp.Status + ": " + strings.TrimSpace(string(b)))
-	}
-
-	var items []struct {
-		Title      string `json:"title"`
-		HrefStatus int    `json:"href_status"`
-		HrefURL    string `json:"href_url"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
-		return latestItem{}, err
-	}
-	if len(items) == 0 {
-		return latestItem{}, nil
-	}
-
-	first := items[0]
-	_ = first.HrefStatus
-	title := strings.TrimSpace(first.Title)
-	link := strings.TrimSpace(first.HrefURL)
-	key := strings.TrimSpace(link)
-	if key == "" {
-		key = strings.TrimSpace(title)
-	}
-	return latestItem{Key: key, Title: title, Link: link}, nil
+	return all[0], nil
 }
 
 func (activityChecker) FetchAll(ctx context.Context, client *http.Client) ([]latestItem, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, activityJSONURL, nil)
+	body, err := fetchPage(ctx, client, activityJSONURL)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		b, _ := io.ReadAll(resp.Body)
-		return nil, errors.New("HTTP " + resp.Status + ": " + strings.TrimSpace(string(b)))
-	}
-
 	var items []struct {
 		Title      string `json:"title"`
 		HrefStatus int    `json:"href_status"`
 		HrefURL    string `json:"href_url"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+	if err := json.Unmarshal(body, &items); err != nil {
 		return nil, err
 	}
 
@@ -192,59 +171,73 @@ func (activityChecker) FetchAll(ctx context.Context, client *http.Client) ([]lat
 	return out, nil
 }
 
-type forumChecker struct{}
+type forumChecker struct {
+	board ForumBoardConfig
+}
 
-func (forumChecker) Name() string     { return "论坛" }
-func (forumChecker) PushHead() string { return "天龙论坛有新帖了" }
+func (c forumChecker) Name() string { return strings.TrimSpace(c.board.Name) }
 
-func (forumChecker) FetchLatest(ctx context.Context, client *http.Client) (latestItem, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, forumListURL, nil)
-	if err != nil {
-		return latestItem{}, err
+func (c forumChecker) PushHead() string {
+	if head := strings.TrimSpace(c.board.PushHead); head != "" {
+		return head
 	}
+	return c.Name() + "有新帖了"
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return latestItem{}, err
+var (
+	forumThreadIDRes = []*regexp.Regexp{
+		regexp.MustCompile(`thread-(\d+)-`),
+		regexp.MustCompile(`[?&]tid=(\d+)`),
+	}
+	forumRowIDRe = regexp.MustCompile(`^(?:stick|normal)thread_(\d+)$`)
+)
+
+// forumThreadID 从帖子链接中解析 tid，支持 thread-123-1-1.html 与 mod=viewthread&tid=123 两种形式。
+func forumThreadID(link string) string {
+	for _, re := range forumThreadIDRes {
+		if m := re.FindStringSubmatch(link); m != nil {
+			return m[1]
+		}
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		b, _ := io.ReadAll(resp.Body)
-		return latestItem{}, errors.New("HTTP " + resp.Status + ": " + strings.TrimSpace(string(b)))
+	return ""
+}
+
+func (c forumChecker) FetchLatest(ctx context.Context, client *http.Client) (latestItem, error) {
+	all, err := c.FetchAll(ctx, client)
+	if err != nil || len(all) == 0 {
+		return latestItem{}, err
 	}
+	return all[0], nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// FetchAll 返回帖子列表中的全部帖子（置顶帖在前），Key 为帖子 tid。
+func (c forumChecker) FetchAll(ctx context.Context, client *http.Client) ([]latestItem, error) {
+	listURL := forumBoardURL(c.board.FID)
+	body, err := fetchPage(ctx, client, listURL)
 	if err != nil {
-		return latestItem{}, err
+		return nil, err
 	}
 
 	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
-		return latestItem{}, err
+		return nil, err
 	}
 
-	base, _ := url.Parse(forumListURL)
+	base, _ := url.Parse(listURL)
 
-	resolveHref := func(href string) string {
-		href = strings.TrimSpace(href)
-		if href == "" {
-			return ""
-		}
-		if base == nil {
-			return href
-		}
-		ref, parseErr := url.Parse(href)
-		if parseErr != nil {
-			return ""
-		}
-		return base.ResolveReference(ref).String()
-	}
-
-	pick := func(sel *goquery.Selection) latestItem {
+	pick := func(sel *goquery.Selection, rowID string) latestItem {
 		title := strings.TrimSpace(sel.Text())
 		href, _ := sel.Attr("href")
-		link := resolveHref(href)
-		key := strings.TrimSpace(link)
+		link := resolveLink(base, href)
+		key := forumThreadID(link)
+		if key == "" {
+			if m := forumRowIDRe.FindStringSubmatch(rowID); m != nil {
+				key = m[1]
+			}
+		}
+		if key == "" {
+			key = strings.TrimSpace(link)
+		}
 		if key == "" {
 			key = strings.TrimSpace(title)
 		}
@@ -267,70 +260,112 @@ func (forumChecker) FetchLatest(ctx context.Context, client *http.Client) (lates
 		return nil
 	}
 
-	rowSelectors := []string{
-		"#threadlisttableid > tbody[id^='stickthread_']",
-		"#threadlisttableid > tbody[id^='normalthread_']",
-	}
-
-	for _, rowSel := range rowSelectors {
-		var found latestItem
-		doc.Find(rowSel).EachWithBreak(func(_ int, row *goquery.Selection) bool {
-			a := findTitleAnchor(row)
-			if a == nil || a.Length() == 0 {
-				return true
-			}
-			item := pick(a)
-			if strings.TrimSpace(item.Key) == "" || strings.TrimSpace(item.Title) == "" {
-				return true
-			}
-			found = item
-			return false
-		})
-		if strings.TrimSpace(found.Key) != "" {
-			return found, nil
+	var out []latestItem
+	seen := map[string]struct{}{}
+	doc.Find("#threadlisttableid > tbody[id^='stickthread_'], #threadlisttableid > tbody[id^='normalthread_']").Each(func(_ int, row *goquery.Selection) {
+		a := findTitleAnchor(row)
+		if a == nil || a.Length() == 0 {
+			return
+		}
+		rowID, _ := row.Attr("id")
+		item := pick(a, rowID)
+		item.Author = strings.TrimSpace(row.Find("td.by cite a").First().Text())
+		if strings.TrimSpace(item.Key) == "" || strings.TrimSpace(item.Title) == "" {
+			return
 		}
+		if _, dup := seen[item.Key]; dup {
+			return
+		}
+		seen[item.Key] = struct{}{}
+		out = append(out, item)
+	})
+	if len(out) > 0 {
+		return out, nil
 	}
 
 	// 兜底：只在 threadlisttableid 区域取标题，避免抓到页面其他 viewthread 链接。
 	fallback := doc.Find("#threadlisttableid th a.s.xst[href*='mod=viewthread'], #threadlisttableid th a.s.xst[href*='thread-'], #threadlisttableid th a.xst[href*='mod=viewthread'], #threadlisttableid th a.xst[href*='thread-']").First()
 	if fallback.Length() > 0 {
-		item := pick(fallback)
+		item := pick(fallback, "")
 		if strings.TrimSpace(item.Key) != "" {
-			return item, nil
+			return []latestItem{item}, nil
 		}
 	}
 
-	return latestItem{}, nil
+	return nil, nil
+}
+
+// builtinCheckers 返回内置的检测来源。
+func builtinCheckers() []checker {
+	return []checker{announcementChecker{}, activityChecker{}, forumChecker{board: defaultForumBoard()}}
+}
+
+// pushCategories 返回不对应检测来源、只用于推送的内置类别，它们同样有默认模板并占用来源名称。
+func pushCategories() []pushSource {
+	return []pushSource{announceEditSource{}, maintenanceSource{}, redeemCodeSource{}}
 }
 
 type Monitor struct {
 	mu sync.Mutex
+	// persistMu 串行化状态文件的写入。需要同时持有时先取 persistMu 再取 mu，保证后取的快照后写入。
+	persistMu sync.Mutex
 
 	appCtx context.Context
 
 	running bool
 	cancel  context.CancelFunc
 
-	channelKey     string
-	lastKey        string
-	lastTitle      string
-	lastActKey     string
-	lastActTitle   string
-	lastActLink    string
-	lastForumKey   string
-	lastForumTitle string
-	lastForumLink  string
-	actSeenKeys    []string
-	lastChecked    time.Time
+	channelKey   string
+	lastKey      string
+	lastTitle    string
+	lastActKey   string
+	lastActTitle string
+	lastActLink  string
+	annSeenKeys  []string
+	actSeenKeys  []string
+	lastChecked  time.Time
+
+	notifierCfgs   []NotifierConfig
+	notifiers      []Notifier
+	pushTemplates  map[string]PushTemplate
+	filterRules    []FilterRule
+	rules          []Rule
+	htmlSources    []HTMLSourceConfig
+	jsonSources    []JSONSourceConfig
+	rssSources     []RSSSourceConfig
+	forumBoards    []ForumBoardConfig
+	forumThreads   []ForumThreadConfig
+	announceDetail AnnounceDetailConfig
+	annContents    map[string]announceContent
+	maintenanceCfg MaintenanceConfig
+	schedules      []SourceSchedule
+	schedProfile   []ScheduleSlot
+	sourceStates   map[string]sourceState
+
+	// burstUntil 之前为加速检查，每 burstInterval 检查一次；burstWake 用于唤醒调度器。
+	burstUntil    time.Time
+	burstInterval time.Duration
+	burstWake     chan struct{}
+
+	outbox    outboxState
+	outboxSeq int
+	history   []HistoryEntry
+
+	maintenance []MaintenanceEvent
+	redeemCodes []string
 
 	httpClient *http.Client
 	rng        *rand.Rand
+	// checkTimeout 为单个来源一次检查的总时长上限，包括读取详情页与推送；创建后不再修改。
+	checkTimeout time.Duration
 }
 
 func NewMonitor() *Monitor {
 	return &Monitor{
-		httpClient: &http.Client{Timeout: 5 * time.Second},
-		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		httpClient:   &http.Client{Timeout: 5 * time.Second},
+		rng:          rand.New(rand.NewSource(time.Now().UnixNano())),
+		burstWake:    make(chan struct{}, 1),
+		checkTimeout: sourceCheckTimeout,
 	}
 }
 
@@ -339,6 +374,17 @@ func (m *Monitor) Attach(appCtx context.Context) {
 	defer m.mu.Unlock()
 	m.appCtx = appCtx
 
+	// 读取上次未发送成功的推送，重启后继续重试。
+	if ob, err := loadOutbox(); err == nil {
+		m.outbox = ob
+	}
+	if h, err := loadHistory(); err == nil {
+		m.history = h
+	}
+	if events, err := loadMaintenance(); err == nil {
+		m.maintenance = events
+	}
+
 	// 读取本地持久化设置：ChannelKey + 上次已读公告/活动，用于跨重启去重与自动回填。
 	if s, err := loadSettings(); err == nil {
 		m.channelKey = strings.TrimSpace(s.ChannelKey)
@@ -347,10 +393,25 @@ func (m *Monitor) Attach(appCtx context.Context) {
 		m.lastActKey = strings.TrimSpace(s.LastActivityKey)
 		m.lastActTitle = strings.TrimSpace(s.LastActivityTitle)
 		m.lastActLink = strings.TrimSpace(s.LastActivityLink)
-		m.lastForumKey = strings.TrimSpace(s.LastForumKey)
-		m.lastForumTitle = strings.TrimSpace(s.LastForumTitle)
-		m.lastForumLink = strings.TrimSpace(s.LastForumLink)
+		m.annSeenKeys = append([]string(nil), s.AnnounceSeenKeys...)
 		m.actSeenKeys = append([]string(nil), s.ActivitySeenKeys...)
+		m.notifierCfgs = append([]NotifierConfig(nil), s.Notifiers...)
+		m.pushTemplates = copyPushTemplates(s.PushTemplates)
+		m.filterRules = append([]FilterRule(nil), s.FilterRules...)
+		m.rules = append([]Rule(nil), s.Rules...)
+		m.htmlSources = append([]HTMLSourceConfig(nil), s.HTMLSources...)
+		m.jsonSources = append([]JSONSourceConfig(nil), s.JSONSources...)
+		m.rssSources = append([]RSSSourceConfig(nil), s.RSSSources...)
+		m.forumBoards = append([]ForumBoardConfig(nil), s.ForumBoards...)
+		m.forumThreads = append([]ForumThreadConfig(nil), s.ForumThreads...)
+		m.announceDetail = s.AnnounceDetail
+		m.annContents = copyAnnounceContents(s.AnnounceContents)
+		m.redeemCodes = append([]string(nil), s.RedeemCodes...)
+		m.maintenanceCfg = s.Maintenance
+		m.schedules = append([]SourceSchedule(nil), s.Schedules...)
+		m.schedProfile = append([]ScheduleSlot(nil), s.ScheduleProfile...)
+		m.sourceStates = copySourceStates(s.SourceStates)
+		m.migrateLegacyForumLocked(s)
 
 		// 兼容旧数据：若只有 title 没有 key，则用 title 作为 key。
 		if m.lastKey == "" {
@@ -359,9 +420,6 @@ func (m *Monitor) Attach(appCtx context.Context) {
 		if m.lastActKey == "" {
 			m.lastActKey = m.lastActTitle
 		}
-		if m.lastForumKey == "" {
-			m.lastForumKey = m.lastForumTitle
-		}
 		if len(m.actSeenKeys) == 0 && m.lastActKey != "" {
 			m.actSeenKeys = []string{m.lastActKey}
 		}
@@ -369,13 +427,125 @@ func (m *Monitor) Attach(appCtx context.Context) {
 }
 
 type AppSettings struct {
-	ChannelKey string `json:"channelKey"`
+	ChannelKey      string                  `json:"channelKey"`
+	Notifiers       []NotifierConfig        `json:"notifiers"`
+	PushTemplates   map[string]PushTemplate `json:"pushTemplates"`
+	FilterRules     []FilterRule            `json:"filterRules"`
+	Rules           []Rule                  `json:"rules"`
+	HTMLSources     []HTMLSourceConfig      `json:"htmlSources"`
+	JSONSources     []JSONSourceConfig      `json:"jsonSources"`
+	RSSSources      []RSSSourceConfig       `json:"rssSources"`
+	ForumBoards     []ForumBoardConfig      `json:"forumBoards"`
+	ForumThreads    []ForumThreadConfig     `json:"forumThreads"`
+	AnnounceDetail  AnnounceDetailConfig    `json:"announceDetail"`
+	Maintenance     MaintenanceConfig       `json:"maintenance"`
+	Schedules       []SourceSchedule        `json:"schedules"`
+	ScheduleProfile []ScheduleSlot          `json:"scheduleProfile"`
 }
 
 func (m *Monitor) GetSettings() AppSettings {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	return AppSettings{ChannelKey: m.channelKey}
+
+	// 未自定义的来源返回默认模板，便于前端直接展示和修改。
+	templates := defaultPushTemplates()
+	for _, c := range m.forumCheckersLocked() {
+		if _, ok := templates[c.Name()]; !ok {
+			templates[c.Name()] = defaultPushTemplate(c)
+		}
+	}
+	for source, t := range m.pushTemplates {
+		templates[source] = t.withDefaults(templates[source])
+	}
+	return AppSettings{
+		ChannelKey:      m.channelKey,
+		Notifiers:       append([]NotifierConfig(nil), m.notifierCfgs...),
+		PushTemplates:   templates,
+		FilterRules:     append([]FilterRule(nil), m.filterRules...),
+		Rules:           append([]Rule(nil), m.rules...),
+		HTMLSources:     append([]HTMLSourceConfig(nil), m.htmlSources...),
+		JSONSources:     append([]JSONSourceConfig(nil), m.jsonSources...),
+		RSSSources:      append([]RSSSourceConfig(nil), m.rssSources...),
+		ForumBoards:     effectiveForumBoards(m.forumBoards),
+		ForumThreads:    append([]ForumThreadConfig(nil), m.forumThreads...),
+		AnnounceDetail:  m.announceDetail.withDefaults(),
+		Maintenance:     m.maintenanceCfg.withDefaults(),
+		Schedules:       append([]SourceSchedule(nil), m.schedules...),
+		ScheduleProfile: append([]ScheduleSlot(nil), m.schedProfile...),
+	}
+}
+
+// SaveSettings 校验并保存设置；监控运行中时立即生效。
+func (m *Monitor) SaveSettings(s AppSettings) error {
+	channelKey := strings.TrimSpace(s.ChannelKey)
+	notifiers, errs := buildNotifiers(channelKey, s.Notifiers)
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	if err := validatePushTemplates(s.PushTemplates); err != nil {
+		return err
+	}
+	if err := validateFilterRules(s.FilterRules); err != nil {
+		return err
+	}
+	if err := validateRules(s.Rules); err != nil {
+		return err
+	}
+	if err := validateForumBoards(s.ForumBoards); err != nil {
+		return err
+	}
+	if err := validatePushHeads(s); err != nil {
+		return err
+	}
+	if err := validateSourceNames(customSourceNames(s)); err != nil {
+		return err
+	}
+	if err := validateHTMLSources(s.HTMLSources); err != nil {
+		return err
+	}
+	if err := validateJSONSources(s.JSONSources); err != nil {
+		return err
+	}
+	if err := validateRSSSources(s.RSSSources); err != nil {
+		return err
+	}
+	if err := validateForumThreads(s.ForumThreads); err != nil {
+		return err
+	}
+	if err := validateAnnounceDetail(s.AnnounceDetail); err != nil {
+		return err
+	}
+	if err := validateMaintenance(s.Maintenance); err != nil {
+		return err
+	}
+	if err := validateSchedules(s.Schedules); err != nil {
+		return err
+	}
+	if err := validateScheduleProfile(s.ScheduleProfile); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.channelKey = channelKey
+	m.notifierCfgs = append([]NotifierConfig(nil), s.Notifiers...)
+	m.pushTemplates = copyPushTemplates(s.PushTemplates)
+	m.filterRules = append([]FilterRule(nil), s.FilterRules...)
+	m.rules = append([]Rule(nil), s.Rules...)
+	m.htmlSources = append([]HTMLSourceConfig(nil), s.HTMLSources...)
+	m.jsonSources = append([]JSONSourceConfig(nil), s.JSONSources...)
+	m.rssSources = append([]RSSSourceConfig(nil), s.RSSSources...)
+	m.forumBoards = append([]ForumBoardConfig(nil), s.ForumBoards...)
+	m.forumThreads = append([]ForumThreadConfig(nil), s.ForumThreads...)
+	m.announceDetail = s.AnnounceDetail
+	m.maintenanceCfg = s.Maintenance
+	m.schedules = append([]SourceSchedule(nil), s.Schedules...)
+	m.schedProfile = append([]ScheduleSlot(nil), s.ScheduleProfile...)
+	if m.running {
+		m.notifiers = notifiers
+	}
+	m.mu.Unlock()
+	m.persistSnapshot()
+	return nil
 }
 
 func (m *Monitor) Status() MonitorStatus {
@@ -387,12 +557,21 @@ func (m *Monitor) Status() MonitorStatus {
 		LastTitle:         m.lastTitle,
 		LastActivityTitle: m.lastActTitle,
 		LastActivityLink:  m.lastActLink,
-		LastForumTitle:    m.lastForumTitle,
-		LastForumLink:     m.lastForumLink,
+		OutboxPending:     len(m.outbox.Pending),
+		OutboxDead:        len(m.outbox.Dead),
+		OutboxRetryLimit:  outboxMaxAttempts,
+		LastDeadLetter:    m.lastDeadLetterLocked(),
+		Sources:           m.sourceStatusesLocked(),
 	}
+	forum := m.primaryForumStateLocked()
+	status.LastForumTitle = forum.LastTitle
+	status.LastForumLink = forum.LastLink
 	if !m.lastChecked.IsZero() {
 		status.LastChecked = m.lastChecked.Format(time.RFC3339)
 	}
+	if m.running && time.Now().Before(m.burstUntil) {
+		status.BurstUntil = m.burstUntil.Format(time.RFC3339)
+	}
 	return status
 }
 
@@ -409,26 +588,22 @@ func (m *Monitor) Start(channelKey string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	m.cancel = cancel
 	appCtx := m.appCtx
-	// 持久化 ChannelKey（允许为空，表示禁用推送）
-	_ = saveSettings(persistedSettings{
-		ChannelKey:        m.channelKey,
-		LastAnnounceKey:   m.lastKey,
-		LastAnnounceTitle: m.lastTitle,
-		LastActivityKey:   m.lastActKey,
-		LastActivityTitle: m.lastActTitle,
-		LastActivityLink:  m.lastActLink,
-		LastForumKey:      m.lastForumKey,
-		LastForumTitle:    m.lastForumTitle,
-		LastForumLink:     m.lastForumLink,
-		ActivitySeenKeys:  append([]string(nil), m.actSeenKeys...),
-	})
+	notifiers, notifierErrs := buildNotifiers(m.channelKey, m.notifierCfgs)
+	m.notifiers = notifiers
 	m.mu.Unlock()
+	// 持久化 ChannelKey（允许为空，表示禁用推送）
+	m.persistSnapshot()
 
 	m.emitLog(appCtx, "INFO", "监控已启动")
-	if channelKey == "" {
-		m.emitLog(appCtx, "WARN", "未填写推送链接/Key：将跳过微信推送，仅打开链接")
+	for _, err := range notifierErrs {
+		m.emitLog(appCtx, "WARN", "推送渠道配置无效，已跳过: "+err.Error())
+	}
+	if len(notifiers) == 0 {
+		m.emitLog(appCtx, "WARN", "未配置推送渠道：将跳过推送，仅打开链接")
 	}
 
+	go m.runMaintenanceReminders(ctx, appCtx)
+	go m.runOutboxRetries(ctx, appCtx)
 	go func() {
 		defer func() {
 			m.mu.Lock()
@@ -438,22 +613,7 @@ func (m *Monitor) Start(channelKey string) error {
 			m.emitLog(appCtx, "INFO", "监控已停止")
 		}()
 
-		for {
-			if err := m.checkOnce(ctx, appCtx); err != nil {
-				m.emitLog(appCtx, "ERROR", "检查失败: "+err.Error())
-			}
-
-			nextSec := m.randomIntervalSec()
-			m.emitLog(appCtx, "INFO", "下次检查将在 "+(time.Duration(nextSec)*time.Second).String()+" 后")
-
-			t := time.NewTimer(time.Duration(nextSec) * time.Second)
-			select {
-			case <-ctx.Done():
-				t.Stop()
-				return
-			case <-t.C:
-			}
-		}
+		m.runScheduler(ctx, appCtx, m.scheduledCheckers)
 	}()
 
 	return nil
@@ -464,6 +624,7 @@ func (m *Monitor) Stop() {
 	cancel := m.cancel
 	m.running = false
 	m.cancel = nil
+	m.burstUntil = time.Time{}
 	appCtx := m.appCtx
 	m.mu.Unlock()
 
@@ -473,367 +634,416 @@ func (m *Monitor) Stop() {
 	}
 }
 
-func (m *Monitor) randomIntervalSec() int {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if maxIntervalSec <= minIntervalSec {
-		return minIntervalSec
-	}
-	return m.rng.Intn(maxIntervalSec-minIntervalSec+1) + minIntervalSec
-}
-
-func (m *Monitor) persistSnapshot() {
-	m.mu.Lock()
-	s := persistedSettings{
+// snapshotLocked 生成待持久化的设置快照，调用方需持有 m.mu。
+func (m *Monitor) snapshotLocked() persistedSettings {
+	return persistedSettings{
 		ChannelKey:        m.channelKey,
+		Notifiers:         append([]NotifierConfig(nil), m.notifierCfgs...),
+		PushTemplates:     copyPushTemplates(m.pushTemplates),
+		FilterRules:       append([]FilterRule(nil), m.filterRules...),
+		Rules:             append([]Rule(nil), m.rules...),
+		HTMLSources:       append([]HTMLSourceConfig(nil), m.htmlSources...),
+		JSONSources:       append([]JSONSourceConfig(nil), m.jsonSources...),
+		RSSSources:        append([]RSSSourceConfig(nil), m.rssSources...),
+		ForumBoards:       append([]ForumBoardConfig(nil), m.forumBoards...),
+		ForumThreads:      append([]ForumThreadConfig(nil), m.forumThreads...),
+		AnnounceDetail:    m.announceDetail,
+		Maintenance:       m.maintenanceCfg,
+		Schedules:         append([]SourceSchedule(nil), m.schedules...),
+		ScheduleProfile:   append([]ScheduleSlot(nil), m.schedProfile...),
+		SourceStates:      copySourceStates(m.sourceStates),
 		LastAnnounceKey:   m.lastKey,
 		LastAnnounceTitle: m.lastTitle,
+		AnnounceSeenKeys:  append([]string(nil), m.annSeenKeys...),
+		AnnounceContents:  copyAnnounceContents(m.annContents),
+		RedeemCodes:       append([]string(nil), m.redeemCodes...),
 		LastActivityKey:   m.lastActKey,
 		LastActivityTitle: m.lastActTitle,
 		LastActivityLink:  m.lastActLink,
-		LastForumKey:      m.lastForumKey,
-		LastForumTitle:    m.lastForumTitle,
-		LastForumLink:     m.lastForumLink,
 		ActivitySeenKeys:  append([]string(nil), m.actSeenKeys...),
 	}
-	m.mu.Unlock()
-	_ = saveSettings(s)
-}
-
-type activityAllFetcher interface {
-	FetchAll(ctx context.Context, client *http.Client) ([]latestItem, error)
 }
 
-func (m *Monitor) checkOnce(ctx context.Context, appCtx context.Context) error {
-	checks := []checker{announcementChecker{}, activityChecker{}, forumChecker{}}
-
-	now := time.Now()
-
+func (m *Monitor) persistSnapshot() {
+	m.persistMu.Lock()
+	defer m.persistMu.Unlock()
 	m.mu.Lock()
-	m.lastChecked = now
-	channelKey := m.channelKey
-	prevAnnKey := m.lastKey
-	prevActKey := m.lastActKey
-	prevForumKey := m.lastForumKey
-	seenAct := append([]string(nil), m.actSeenKeys...)
+	s := m.snapshotLocked()
 	m.mu.Unlock()
+	_ = saveSettings(s)
+}
 
-	allFailed := true
-	for _, c := range checks {
-		item, err := c.FetchLatest(ctx, m.httpClient)
-		if err != nil {
-			m.emitLog(appCtx, "ERROR", c.Name()+"检查失败: "+err.Error())
-			continue
-		}
-		allFailed = false
-		if strings.TrimSpace(item.Key) == "" {
-			m.emitLog(appCtx, "WARN", "未找到最新"+c.Name()+"标题")
-			continue
-		}
+// checkRun 为一次检查开始时取出的配置快照，检查过程中不受设置修改影响。
+type checkRun struct {
+	now       time.Time
+	notifiers []Notifier
+	templates map[string]PushTemplate
+	filters   []FilterRule
+	rules     []Rule
+	announce  AnnounceDetailConfig
+}
 
-		switch c.Name() {
-		case "公告":
-			if strings.TrimSpace(prevAnnKey) == "" {
-				m.mu.Lock()
-				m.lastKey = item.Key
-				m.lastTitle = item.Title
-				m.mu.Unlock()
-				m.persistSnapshot()
-				m.emitLog(appCtx, "INFO", "已获取当前最新公告(基线): "+item.Title)
-				prevAnnKey = item.Key
-				continue
-			}
-			if item.Key == prevAnnKey {
-				m.emitLog(appCtx, "INFO", "公告未发生变化: "+item.Title)
-				continue
-			}
+// newCheckRunLocked 生成配置快照，调用方需持有 m.mu。
+func (m *Monitor) newCheckRunLocked(now time.Time) checkRun {
+	return checkRun{
+		now:       now,
+		notifiers: append([]Notifier(nil), m.notifiers...),
+		templates: copyPushTemplates(m.pushTemplates),
+		filters:   append([]FilterRule(nil), m.filterRules...),
+		rules:     append([]Rule(nil), m.rules...),
+		announce:  m.announceDetail.withDefaults(),
+	}
+}
 
-			m.emitLog(appCtx, "INFO", "检测到新公告: "+item.Title)
-			m.mu.Lock()
-			m.lastKey = item.Key
-			m.lastTitle = item.Title
-			m.mu.Unlock()
-			m.persistSnapshot()
+// deliver 处理一个已记入已见状态的新条目：先按规则、再按过滤规则决定要执行的动作。
+func (m *Monitor) deliver(ctx context.Context, appCtx context.Context, run checkRun, c pushSource, item latestItem) {
+	plan := planActions(run.rules, run.filters, c.Name(), item.Title, run.now)
+	notified := len(plan.actions) > 0 && plan.actions[0].Type != ruleActionSuppress
+	m.recordHistory(HistoryEntry{
+		Source:     c.Name(),
+		Title:      item.Title,
+		Link:       item.Link,
+		Author:     item.Author,
+		Content:    item.Body,
+		Notified:   notified,
+		DetectedAt: run.now,
+	})
+	if len(plan.matchedRules) > 0 {
+		m.emitLog(appCtx, "INFO", c.Name()+"命中规则: "+strings.Join(plan.matchedRules, "、"))
+	}
+	if len(plan.actions) == 0 {
+		m.emitLog(appCtx, "INFO", c.Name()+"未通过过滤规则，仅记录: "+item.Title)
+		return
+	}
+	if len(plan.keywords) > 0 {
+		m.emitLog(appCtx, "INFO", c.Name()+"命中关键词: "+strings.Join(plan.keywords, "、"))
+	}
 
+	for _, a := range plan.actions {
+		switch a.Type {
+		case ruleActionOpen:
 			if strings.TrimSpace(item.Link) != "" {
-				runtime.BrowserOpenURL(appCtx, item.Link)
-				m.emitLog(appCtx, "INFO", "已打开公告链接: "+item.Link)
-			} else {
-				m.emitLog(appCtx, "WARN", "未解析到公告链接")
-			}
-
-			if strings.TrimSpace(channelKey) == "" {
-				m.emitLog(appCtx, "INFO", "未配置推送链接/Key，已跳过微信推送")
-			} else {
-				if err := m.sendWechatPush(ctx, channelKey, c.PushHead(), item.Title, item.Link); err != nil {
-					m.emitLog(appCtx, "ERROR", "微信推送失败: "+err.Error())
-				} else {
-					m.emitLog(appCtx, "INFO", "微信推送发送成功")
+				if appCtx != nil {
+					runtime.BrowserOpenURL(appCtx, item.Link)
 				}
+				m.emitLog(appCtx, "INFO", "已打开"+c.Name()+"链接: "+item.Link)
+			} else {
+				m.emitLog(appCtx, "WARN", "未解析到"+c.Name()+"链接")
 			}
-			prevAnnKey = item.Key
-
-		case "活动":
-			af, ok := c.(activityAllFetcher)
-			if !ok {
-				// 理论不会发生；兜底：仍按单条逻辑处理
-				if item.Key == prevActKey {
-					m.emitLog(appCtx, "INFO", "活动未发生变化: "+item.Title)
-					continue
-				}
-				m.emitLog(appCtx, "INFO", "检测到新活动: "+item.Title)
-				m.mu.Lock()
-				m.lastActKey = item.Key
-				m.lastActTitle = item.Title
-				m.lastActLink = item.Link
-				m.mu.Unlock()
-				m.persistSnapshot()
-				prevActKey = item.Key
-				continue
-			}
-
-			all, err := af.FetchAll(ctx, m.httpClient)
-			if err != nil {
-				m.emitLog(appCtx, "ERROR", "活动检查失败: "+err.Error())
+		case ruleActionPush:
+			notifiers := selectNotifiers(run.notifiers, a.Notifiers)
+			if len(notifiers) == 0 && len(a.Notifiers) > 0 {
+				m.emitLog(appCtx, "WARN", "规则指定的推送渠道不存在或未启用: "+strings.Join(a.Notifiers, "、"))
 				continue
 			}
-			if len(all) == 0 {
-				m.emitLog(appCtx, "WARN", "未找到最新活动标题")
-				continue
-			}
-
-			// 基线：首次运行时把整个列表作为已见集合，避免第一次就打开/推送。
-			if strings.TrimSpace(prevActKey) == "" {
-				keys := make([]string, 0, len(all))
-				for _, it := range all {
-					keys = append(keys, it.Key)
-				}
-				m.mu.Lock()
-				m.lastActKey = all[0].Key
-				m.lastActTitle = all[0].Title
-				m.lastActLink = all[0].Link
-				m.actSeenKeys = keys
-				m.mu.Unlock()
-				m.persistSnapshot()
-				m.emitLog(appCtx, "INFO", "已获取当前最新活动(基线): "+all[0].Title)
-				prevActKey = all[0].Key
-				seenAct = append([]string(nil), keys...)
-				continue
-			}
-
-			seenSet := map[string]struct{}{}
-			for _, k := range seenAct {
-				k = strings.TrimSpace(k)
-				if k == "" {
-					continue
-				}
-				seenSet[k] = struct{}{}
+			m.pushItem(ctx, appCtx, run, notifiers, c, item, plan.keywords)
+		case ruleActionSound:
+			if appCtx != nil {
+				runtime.EventsEmit(appCtx, "sound", a.Sound)
 			}
+		case ruleActionCommand:
+			go m.runRuleCommand(appCtx, a, c.Name(), item)
+		case ruleActionSuppress:
+			m.emitLog(appCtx, "INFO", c.Name()+"规则要求停止后续动作: "+item.Title)
+			return
+		}
+	}
+}
 
-			var newItems []latestItem
-			for _, it := range all {
-				if _, ok := seenSet[it.Key]; !ok {
-					newItems = append(newItems, it)
-				}
-			}
+// allFetcher 由能返回整个列表的来源实现，用于按已见集合逐条发现新增。
+type allFetcher interface {
+	FetchAll(ctx context.Context, client *http.Client) ([]latestItem, error)
+}
 
-			if len(newItems) == 0 {
-				m.emitLog(appCtx, "INFO", "活动未发现新增: "+all[0].Title)
-				continue
-			}
+// maxSeenKeys 为每个来源保留的已见 key 数量上限。
+const maxSeenKeys = 200
 
-			picked := newItems[len(newItems)-1]
-			m.emitLog(appCtx, "INFO", "检测到新活动: "+picked.Title)
+// diffSeen 返回 all 中不在 seen 里的条目（保持原顺序），以及追加这些 key 并限制长度后的已见列表。
+func diffSeen(all []latestItem, seen []string) ([]latestItem, []string) {
+	seenSet := map[string]struct{}{}
+	for _, k := range seen {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			continue
+		}
+		seenSet[k] = struct{}{}
+	}
 
-			// 更新已见列表并限制长度
-			for _, it := range newItems {
-				seenAct = append(seenAct, it.Key)
-			}
-			const maxSeen = 200
-			if len(seenAct) > maxSeen {
-				seenAct = seenAct[len(seenAct)-maxSeen:]
-			}
+	var newItems []latestItem
+	for _, it := range all {
+		if _, ok := seenSet[it.Key]; !ok {
+			newItems = append(newItems, it)
+			seenSet[it.Key] = struct{}{}
+		}
+	}
 
-			m.mu.Lock()
-			m.lastActKey = picked.Key
-			m.lastActTitle = picked.Title
-			m.lastActLink = picked.Link
-			m.actSeenKeys = append([]string(nil), seenAct...)
-			m.mu.Unlock()
-			m.persistSnapshot()
+	updated := append([]string(nil), seen...)
+	for _, it := range newItems {
+		updated = append(updated, it.Key)
+	}
+	if len(updated) > maxSeenKeys {
+		updated = updated[len(updated)-maxSeenKeys:]
+	}
+	return newItems, updated
+}
 
-			if strings.TrimSpace(picked.Link) != "" {
-				runtime.BrowserOpenURL(appCtx, picked.Link)
-				m.emitLog(appCtx, "INFO", "已打开活动链接: "+picked.Link)
-			} else {
-				m.emitLog(appCtx, "WARN", "未解析到活动链接")
-			}
+// maxThreadID 返回已见 key 中最大的数字 tid，没有时返回 0。
+func maxThreadID(keys []string) int {
+	maxID := 0
+	for _, k := range keys {
+		if id, err := strconv.Atoi(k); err == nil && id > maxID {
+			maxID = id
+		}
+	}
+	return maxID
+}
 
-			if strings.TrimSpace(channelKey) == "" {
-				m.emitLog(appCtx, "INFO", "未配置推送链接/Key，已跳过微信推送")
-			} else {
-				if err := m.sendWechatPush(ctx, channelKey, c.PushHead(), picked.Title, picked.Link); err != nil {
-					m.emitLog(appCtx, "ERROR", "微信推送失败: "+err.Error())
-				} else {
-					m.emitLog(appCtx, "INFO", "微信推送发送成功")
-				}
-			}
-			prevActKey = picked.Key
-
-		case "论坛":
-			if strings.TrimSpace(prevForumKey) == "" {
-				m.mu.Lock()
-				m.lastForumKey = item.Key
-				m.lastForumTitle = item.Title
-				m.lastForumLink = item.Link
-				m.mu.Unlock()
-				m.persistSnapshot()
-				m.emitLog(appCtx, "INFO", "已获取当前最新论坛帖子(基线): "+item.Title)
-				prevForumKey = item.Key
-				continue
-			}
+// itemKeys 返回条目的 key 列表。
+func itemKeys(items []latestItem) []string {
+	keys := make([]string, 0, len(items))
+	for _, it := range items {
+		keys = append(keys, it.Key)
+	}
+	return keys
+}
 
-			if item.Key == prevForumKey {
-				m.emitLog(appCtx, "INFO", "论坛首帖未发生变化: "+item.Title)
-				continue
-			}
+// checkSource 检查单个来源，只有请求或解析失败时返回错误。
+func (m *Monitor) checkSource(ctx context.Context, appCtx context.Context, c checker) error {
+	now := time.Now()
 
-			m.emitLog(appCtx, "INFO", "检测到论坛新帖: "+item.Title)
-			m.mu.Lock()
-			m.lastForumKey = item.Key
-			m.lastForumTitle = item.Title
-			m.lastForumLink = item.Link
-			m.mu.Unlock()
-			m.persistSnapshot()
+	m.mu.Lock()
+	m.lastChecked = now
+	run := m.newCheckRunLocked(now)
+	m.mu.Unlock()
 
-			if strings.TrimSpace(item.Link) != "" {
-				runtime.BrowserOpenURL(appCtx, item.Link)
-				m.emitLog(appCtx, "INFO", "已打开论坛帖子链接: "+item.Link)
-			} else {
-				m.emitLog(appCtx, "WARN", "未解析到论坛帖子链接")
-			}
+	// 关注的帖子需要读取回复数与各楼作者，单独处理。
+	if tc, ok := c.(threadChecker); ok {
+		return m.checkForumThread(ctx, appCtx, run, tc)
+	}
 
-			if strings.TrimSpace(channelKey) == "" {
-				m.emitLog(appCtx, "INFO", "未配置推送链接/Key，已跳过微信推送")
-			} else {
-				if err := m.sendWechatPush(ctx, channelKey, c.PushHead(), item.Title, item.Link); err != nil {
-					m.emitLog(appCtx, "ERROR", "微信推送失败: "+err.Error())
-				} else {
-					m.emitLog(appCtx, "INFO", "微信推送发送成功")
-				}
-			}
-			prevForumKey = item.Key
+	// 能返回整个列表的来源只请求一次列表，最新一条即列表首项。
+	var all []latestItem
+	var err error
+	if af, ok := c.(allFetcher); ok {
+		all, err = af.FetchAll(ctx, m.httpClient)
+	} else {
+		var latest latestItem
+		latest, err = c.FetchLatest(ctx, m.httpClient)
+		if strings.TrimSpace(latest.Key) != "" {
+			all = []latestItem{latest}
 		}
 	}
+	if err != nil {
+		return err
+	}
+	if len(all) == 0 {
+		m.emitLog(appCtx, "WARN", "未找到最新"+c.Name()+"标题")
+		return nil
+	}
 
-	if allFailed {
-		return errors.New("公告、活动与论坛检查均失败")
+	switch c.Name() {
+	case "公告":
+		m.checkAnnouncements(ctx, appCtx, run, c, all)
+	case "活动":
+		m.checkActivities(ctx, appCtx, run, c, all)
+	default:
+		if fc, ok := c.(forumChecker); ok {
+			m.checkForumBoard(ctx, appCtx, run, fc, all)
+			return nil
+		}
+		m.checkListSource(ctx, appCtx, run, c, all)
 	}
 	return nil
 }
 
-func (m *Monitor) sendWechatPush(ctx context.Context, channelKey string, head string, title string, link string) error {
-	channelKey = strings.TrimSpace(channelKey)
-	if channelKey == "" {
-		return errors.New("推送链接/Key 不能为空")
+func (m *Monitor) checkAnnouncements(ctx context.Context, appCtx context.Context, run checkRun, c checker, all []latestItem) {
+	item := all[0]
+	m.checkAnnounceEdits(ctx, appCtx, run, all)
+
+	m.mu.Lock()
+	prevAnnKey := m.lastKey
+	seenAnn := append([]string(nil), m.annSeenKeys...)
+	m.mu.Unlock()
+
+	// 兼容旧数据：只记录过首条公告时，把列表中该公告及其之后的公告视为已见。
+	if len(seenAnn) == 0 && strings.TrimSpace(prevAnnKey) != "" {
+		for i, it := range all {
+			if it.Key == prevAnnKey {
+				seenAnn = itemKeys(all[i:])
+				break
+			}
+		}
 	}
-	head = strings.TrimSpace(head)
-	if head == "" {
-		head = "消息通知"
+
+	// 基线：首次运行时把整个列表作为已见集合，避免第一次就打开/推送。
+	if len(seenAnn) == 0 {
+		seenAnn = itemKeys(all)
+		m.mu.Lock()
+		m.lastKey = item.Key
+		m.lastTitle = item.Title
+		m.annSeenKeys = append([]string(nil), seenAnn...)
+		m.mu.Unlock()
+		m.persistSnapshot()
+		m.emitLog(appCtx, "INFO", "已获取当前最新公告(基线): "+item.Title)
+		return
 	}
-	body := buildRichPushContent(title, link)
 
-	pushURL, err := buildXizhiPushURL(channelKey, head, body)
-	if err != nil {
-		return err
+	newItems, updated := diffSeen(all, seenAnn)
+	if len(newItems) == 0 {
+		m.emitLog(appCtx, "INFO", "公告未发生变化: "+item.Title)
+		return
 	}
 
-	pushClient := &http.Client{Timeout: 10 * time.Second}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pushURL, nil)
-	if err != nil {
-		return err
+	m.mu.Lock()
+	m.lastKey = item.Key
+	m.lastTitle = item.Title
+	m.annSeenKeys = updated
+	m.mu.Unlock()
+	m.persistSnapshot()
+
+	// 列表最新的在前，按发布顺序从旧到新逐条处理。
+	for i := len(newItems) - 1; i >= 0; i-- {
+		it := newItems[i]
+		m.emitLog(appCtx, "INFO", "检测到新公告: "+it.Title)
+		m.attachAnnounceBody(ctx, appCtx, run, &it)
+		m.recordMaintenance(appCtx, it, run.now)
+		m.deliver(ctx, appCtx, run, c, it)
+		// 兑换码提醒在公告本身之后发出。
+		m.checkRedeemCodes(ctx, appCtx, run, c, it)
 	}
+}
 
-	resp, err := pushClient.Do(req)
-	if err != nil {
-		return err
+func (m *Monitor) checkActivities(ctx context.Context, appCtx context.Context, run checkRun, c checker, all []latestItem) {
+	m.mu.Lock()
+	prevActKey := m.lastActKey
+	seenAct := append([]string(nil), m.actSeenKeys...)
+	m.mu.Unlock()
+
+	// 基线：首次运行时把整个列表作为已见集合，避免第一次就打开/推送。
+	if strings.TrimSpace(prevActKey) == "" {
+		m.mu.Lock()
+		m.lastActKey = all[0].Key
+		m.lastActTitle = all[0].Title
+		m.lastActLink = all[0].Link
+		m.actSeenKeys = itemKeys(all)
+		m.mu.Unlock()
+		m.persistSnapshot()
+		m.emitLog(appCtx, "INFO", "已获取当前最新活动(基线): "+all[0].Title)
+		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return errors.New("HTTP " + resp.Status + ": " + strings.TrimSpace(string(respBody)))
+	newItems, updated := diffSeen(all, seenAct)
+	if len(newItems) == 0 {
+		m.emitLog(appCtx, "INFO", "活动未发现新增: "+all[0].Title)
+		return
 	}
 
-	return nil
+	picked := newItems[len(newItems)-1]
+	m.emitLog(appCtx, "INFO", "检测到新活动: "+picked.Title)
+
+	m.mu.Lock()
+	m.lastActKey = picked.Key
+	m.lastActTitle = picked.Title
+	m.lastActLink = picked.Link
+	m.actSeenKeys = updated
+	m.mu.Unlock()
+	m.persistSnapshot()
+
+	m.deliver(ctx, appCtx, run, c, picked)
+
+	// 活动页中可能直接贴出兑换码，整页文字只用于查找兑换码。
+	// 只打开最早的一个新活动，但每个新活动都要查找兑换码。
+	for i := len(newItems) - 1; i >= 0; i-- {
+		page := newItems[i]
+		m.attachPageText(ctx, appCtx, &page)
+		m.checkRedeemCodes(ctx, appCtx, run, c, page)
+	}
 }
 
-func buildRichPushContent(title string, link string) string {
-	title = strings.TrimSpace(title)
-	link = strings.TrimSpace(link)
+// newPushMessage 按来源的推送模板用检测到的新条目生成推送内容。
+// 模板渲染失败时退回默认模板，默认模板也失败时（来源的推送标题有误）直接拼出默认格式的正文，并返回错误供调用方记录。
+func newPushMessage(c pushSource, item latestItem, detectedAt time.Time, tpl PushTemplate, keywords []string) (PushMessage, error) {
+	def := defaultPushTemplate(c)
+	data := newPushTemplateData(c.Name(), item.Title, item.Link, keywords, detectedAt)
+	data.Summary = item.Summary
 
-	if title == "" {
-		title = "有新消息"
+	head, body, err := tpl.withDefaults(def).render(data)
+	if err != nil {
+		var defErr error
+		if head, body, defErr = def.render(data); defErr != nil {
+			head, body = "", plainPushBody(data)
+		}
 	}
-	if link == "" {
-		return title + "\n来源：天龙怀旧公告检测\n链接：无"
+	if head == "" {
+		head = "消息通知"
 	}
+	return PushMessage{
+		Source:     c.Name(),
+		Head:       head,
+		Title:      item.Title,
+		Link:       item.Link,
+		Summary:    item.Summary,
+		Priority:   pushPriority(c),
+		Body:       body,
+		Keywords:   data.Keywords,
+		DetectedAt: detectedAt,
+	}, err
+}
 
-	return title + "\n来源：天龙怀旧公告检测\n链接：" + link
+// pushItem 生成推送内容并发送到所有渠道。
+func (m *Monitor) pushItem(ctx context.Context, appCtx context.Context, run checkRun, notifiers []Notifier, c pushSource, item latestItem, keywords []string) {
+	msg, err := newPushMessage(c, item, run.now, run.templates[c.Name()], keywords)
+	if err != nil {
+		m.emitLog(appCtx, "WARN", c.Name()+"推送模板渲染失败，已使用默认模板: "+err.Error())
+	}
+	m.pushAll(ctx, appCtx, notifiers, msg)
 }
 
-func buildXizhiPushURL(pushInput string, title string, content string) (string, error) {
-	pushInput = strings.TrimSpace(pushInput)
-	if pushInput == "" {
-		return "", errors.New("推送链接/Key 不能为空")
+// DryRunRules 用示例条目试运行当前的规则与过滤规则，返回会执行的动作，不会真正执行。
+func (m *Monitor) DryRunRules(sample RuleSample) (RuleDryRun, error) {
+	at := time.Now()
+	if t := strings.TrimSpace(sample.Time); t != "" {
+		parsed, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return RuleDryRun{}, errors.New("时间格式应为 RFC3339: " + t)
+		}
+		at = parsed.In(beijingTime)
 	}
 
-	title = strings.TrimSpace(title)
-	if title == "" {
-		title = "消息通知"
+	m.mu.Lock()
+	rules := append([]Rule(nil), m.rules...)
+	filters := append([]FilterRule(nil), m.filterRules...)
+	m.mu.Unlock()
+
+	return planActions(rules, filters, strings.TrimSpace(sample.Source), sample.Title, at).dryRun(), nil
+}
+
+// SendTest 用示例消息测试单个推送渠道，便于在开始监控前检查配置与模板。
+func (m *Monitor) SendTest(ctx context.Context, cfg NotifierConfig) error {
+	n, err := newNotifier(cfg)
+	if err != nil {
+		return err
 	}
-	content = strings.TrimSpace(content)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	return n.Send(ctx, samplePushMessage())
+}
 
-	// 1) 允许直接粘贴完整链接，例如：
-	// https://xizhi.qqoq.net/XZxxxx.send
-	if strings.Contains(pushInput, "://") {
-		u, err := url.Parse(pushInput)
-		if err != nil {
-			return "", err
-		}
-		if u.Scheme == "" || u.Host == "" {
-			return "", errors.New("无效推送链接")
+// pushAll 把消息发送到每个推送渠道，并逐个渠道记录结果。
+// 发送失败的渠道会写入发件箱，之后按指数退避重试。
+func (m *Monitor) pushAll(ctx context.Context, appCtx context.Context, notifiers []Notifier, msg PushMessage) {
+	if len(notifiers) == 0 {
+		m.emitLog(appCtx, "INFO", "未配置推送渠道，已跳过推送")
+		return
+	}
+	for _, n := range notifiers {
+		if err := n.Send(ctx, msg); err != nil {
+			m.emitLog(appCtx, "ERROR", n.Name()+"推送失败，稍后重试: "+err.Error())
+			m.enqueueOutbox(n.Name(), msg, err)
+			continue
 		}
-		q := u.Query()
-		q.Set("title", title)
-		q.Set("content", content)
-		u.RawQuery = q.Encode()
-		return u.String(), nil
-	}
-
-	// 2) 允许只填 key 或填 "XZxxxx.send"
-	key := strings.TrimSpace(pushInput)
-	if strings.Contains(key, "/") {
-		parts := strings.Split(key, "/")
-		key = strings.TrimSpace(parts[len(parts)-1])
-	}
-	key = strings.TrimSuffix(key, ".send")
-	key = strings.TrimSpace(key)
-	if key == "" {
-		return "", errors.New("无效推送 key")
-	}
-
-	u := &url.URL{
-		Scheme: "https",
-		Host:   xizhiDefaultHost,
-		Path:   "/" + key + ".send",
-	}
-	q := u.Query()
-	q.Set("title", title)
-	q.Set("content", content)
-	u.RawQuery = q.Encode()
-	return u.String(), nil
+		m.emitLog(appCtx, "INFO", n.Name()+"推送发送成功")
+	}
 }
 
 func (m *Monitor) emitLog(appCtx context.Context, level string, msg string) {
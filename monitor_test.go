@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiffSeen(t *testing.T) {
+	items := func(keys ...string) []latestItem {
+		var out []latestItem
+		for _, k := range keys {
+			out = append(out, latestItem{Key: k, Title: k})
+		}
+		return out
+	}
+	tests := []struct {
+		name        string
+		all         []latestItem
+		seen        []string
+		wantNew     []string
+		wantUpdated []string
+	}{
+		{"没有新增", items("b", "a"), []string{"a", "b"}, nil, []string{"a", "b"}},
+		{"保持列表顺序", items("d", "c", "b"), []string{"a", "b"}, []string{"d", "c"}, []string{"a", "b", "d", "c"}},
+		{"列表内重复只算一次", items("c", "c", "a"), []string{"a"}, []string{"c"}, []string{"a", "c"}},
+		{"忽略空白 key", items("a"), []string{" ", "b"}, []string{"a"}, []string{" ", "b", "a"}},
+	}
+	for _, tt := range tests {
+		newItems, updated := diffSeen(tt.all, tt.seen)
+		if got := itemKeys(newItems); !slices.Equal(got, tt.wantNew) || !slices.Equal(updated, tt.wantUpdated) {
+			t.Errorf("%s: new=%v updated=%v, 期望 new=%v updated=%v", tt.name, got, updated, tt.wantNew, tt.wantUpdated)
+		}
+	}
+
+	var seen []string
+	for i := 0; i < maxSeenKeys; i++ {
+		seen = append(seen, fmt.Sprint(i))
+	}
+	_, updated := diffSeen(items("x", "y"), seen)
+	if len(updated) != maxSeenKeys || updated[0] != "2" || updated[len(updated)-1] != "y" {
+		t.Errorf("超出上限时应丢弃最旧的 key: %v…%v", updated[:2], updated[len(updated)-2:])
+	}
+}
+
+func TestCheckAnnouncementsReportsEveryNewItem(t *testing.T) {
+	ann := func(n int) latestItem {
+		return latestItem{Key: fmt.Sprintf("n%d", n), Title: fmt.Sprintf("公告%d", n)}
+	}
+	list := func(ns ...int) []latestItem {
+		var out []latestItem
+		for _, n := range ns {
+			out = append(out, ann(n))
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		// lastKey 为旧版本只记录首条公告时留下的 key
+		lastKey string
+		steps   [][]latestItem
+		want    [][]string
+	}{
+		{
+			name:  "首次为基线，之后按发布顺序逐条报告",
+			steps: [][]latestItem{list(3, 2, 1), list(5, 4, 3, 2), list(5, 4, 3, 2), list(6, 5, 4)},
+			want:  [][]string{nil, {"公告4", "公告5"}, nil, {"公告6"}},
+		},
+		{
+			name:    "从旧版本的首条公告继续",
+			lastKey: "n2",
+			steps:   [][]latestItem{list(4, 3, 2, 1)},
+			want:    [][]string{{"公告3", "公告4"}},
+		},
+	}
+	for _, tt := range tests {
+		useTempConfigDir(t)
+		m := NewMonitor()
+		m.lastKey = tt.lastKey
+
+		reported := 0
+		for i, all := range tt.steps {
+			m.mu.Lock()
+			run := m.newCheckRunLocked(time.Now())
+			m.mu.Unlock()
+			m.checkAnnouncements(context.Background(), nil, run, announcementChecker{}, all)
+
+			history := m.History()
+			var got []string
+			for j := len(history) - reported - 1; j >= 0; j-- {
+				got = append(got, history[j].Title)
+			}
+			reported = len(history)
+			if !slices.Equal(got, tt.want[i]) {
+				t.Errorf("%s 第 %d 次: 报告 %v, 期望 %v", tt.name, i+1, got, tt.want[i])
+			}
+			if m.lastKey != all[0].Key {
+				t.Errorf("%s 第 %d 次: lastKey = %s", tt.name, i+1, m.lastKey)
+			}
+		}
+	}
+}
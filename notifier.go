@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const xizhiDefaultHost = "xizhi.qqoq.net"
+
+// PushMessage 是一条待推送的通知内容，由检测到的新条目生成，交给各推送渠道发送。
+// Priority 为 "high" 时表示需要尽快查看（如兑换码），支持的渠道会以更醒目的方式提醒。
+// Recipients 非空时只发给其中的接收方，用于失败重试时跳过已发送成功的接收方（目前仅 Telegram 的 chat ID）。
+type PushMessage struct {
+	Source     string    `json:"source"`
+	Head       string    `json:"head"`
+	Title      string    `json:"title"`
+	Link       string    `json:"link"`
+	Summary    string    `json:"summary,omitempty"`
+	Priority   string    `json:"priority,omitempty"`
+	Body       string    `json:"body"`
+	Keywords   []string  `json:"keywords,omitempty"`
+	Recipients []string  `json:"recipients,omitempty"`
+	DetectedAt time.Time `json:"detectedAt"`
+}
+
+// pushPriorityHigh 为高优先级推送。
+const pushPriorityHigh = "high"
+
+// pushPriority 返回来源的推送优先级，来源未指定时为空。
+func pushPriority(c pushSource) string {
+	if p, ok := c.(interface{ Priority() string }); ok {
+		return p.Priority()
+	}
+	return ""
+}
+
+// partialSendError 为有多个接收方的渠道部分发送失败时返回的错误：
+// 重试时只需发给 Recipients，且至少等待 RetryAfter（如 Telegram 的限流时长）。
+type partialSendError struct {
+	Recipients []string
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *partialSendError) Error() string { return e.Err.Error() }
+func (e *partialSendError) Unwrap() error { return e.Err }
+
+// retryHint 从发送错误中取出重试提示：需要重发的接收方（空表示全部）与最短等待时间。
+func retryHint(err error) ([]string, time.Duration) {
+	var pe *partialSendError
+	if !errors.As(err, &pe) {
+		return nil, 0
+	}
+	return pe.Recipients, pe.RetryAfter
+}
+
+// Notifier 是一个推送渠道。一条新消息会依次发给所有启用的渠道。
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, msg PushMessage) error
+}
+
+// NotifierConfig 是推送渠道的持久化配置。
+// Target 为渠道的主参数（推送链接、Key、Webhook 地址等），其余参数放在 Options 中。
+type NotifierConfig struct {
+	Type    string            `json:"type"`
+	Name    string            `json:"name"`
+	Enabled bool              `json:"enabled"`
+	Target  string            `json:"target"`
+	Options map[string]string `json:"options,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type notifierFactory func(cfg NotifierConfig) (Notifier, error)
+
+// notifierFactories 为按类型注册的推送渠道实现。
+var notifierFactories = map[string]notifierFactory{
+	"auto":       newAutoNotifier,
+	"xizhi":      newXizhiNotifier,
+	"serverchan": newServerChanNotifier,
+	"pushplus":   newPushPlusNotifier,
+	"bark":       newBarkNotifier,
+	"dingtalk":   newDingTalkNotifier,
+	"feishu":     newFeishuNotifier,
+	"lark":       newLarkNotifier,
+	"wecom":      newWecomNotifier,
+	"telegram":   newTelegramNotifier,
+	"smtp":       newSMTPNotifier,
+	"webhook":    newWebhookNotifier,
+}
+
+// pushHTTPClient 供各推送渠道共用。
+var pushHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+func (c NotifierConfig) option(key string) string {
+	if c.Options == nil {
+		return ""
+	}
+	return strings.TrimSpace(c.Options[key])
+}
+
+// notifierName 返回渠道名称，未命名时使用服务默认名称。
+func notifierName(cfg NotifierConfig, fallback string) string {
+	if name := strings.TrimSpace(cfg.Name); name != "" {
+		return name
+	}
+	return fallback
+}
+
+func (c NotifierConfig) displayName() string {
+	if name := strings.TrimSpace(c.Name); name != "" {
+		return name
+	}
+	return strings.TrimSpace(c.Type)
+}
+
+// newNotifier 按配置类型构造推送渠道。
+func newNotifier(cfg NotifierConfig) (Notifier, error) {
+	kind := strings.ToLower(strings.TrimSpace(cfg.Type))
+	factory, ok := notifierFactories[kind]
+	if !ok {
+		return nil, errors.New("未知推送渠道类型: " + cfg.Type)
+	}
+	cfg.Type = kind
+	return factory(cfg)
+}
+
+// buildNotifiers 根据主界面的推送链接/Key 与设置中的渠道列表构造所有启用的推送渠道。
+// 主界面的链接/Key 会自动识别所属推送服务。单个渠道配置有误时跳过该渠道，并在 errs 中返回原因。
+// 渠道名称用于失败重试与规则指定渠道，因此启用的渠道名称不能重复，重名的渠道同样跳过。
+func buildNotifiers(channelKey string, cfgs []NotifierConfig) ([]Notifier, []error) {
+	var out []Notifier
+	var errs []error
+	used := map[string]bool{}
+
+	if key := strings.TrimSpace(channelKey); key != "" {
+		n, err := newNotifier(NotifierConfig{Type: "auto", Enabled: true, Target: key})
+		if err != nil {
+			errs = append(errs, errors.New("推送链接/Key: "+err.Error()))
+		} else {
+			used[n.Name()] = true
+			out = append(out, n)
+		}
+	}
+
+	for _, cfg := range cfgs {
+		if !cfg.Enabled {
+			continue
+		}
+		n, err := newNotifier(cfg)
+		if err != nil {
+			errs = append(errs, errors.New(cfg.displayName()+": "+err.Error()))
+			continue
+		}
+		if used[n.Name()] {
+			errs = append(errs, errors.New("推送渠道名称重复: "+n.Name()+"，请为同类渠道分别填写名称"))
+			continue
+		}
+		used[n.Name()] = true
+		out = append(out, n)
+	}
+	return out, errs
+}
+
+// doPushRequest 发送请求并把非 2xx 响应转换为错误，成功时返回响应体。
+func doPushRequest(req *http.Request) ([]byte, error) {
+	resp, err := pushHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.New("HTTP " + resp.Status + ": " + strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+type xizhiNotifier struct {
+	name string
+	key  string
+}
+
+func newXizhiNotifier(cfg NotifierConfig) (Notifier, error) {
+	key := strings.TrimSpace(cfg.Target)
+	if key == "" {
+		return nil, errors.New("推送链接/Key 不能为空")
+	}
+	// 提前校验，避免配置错误到推送时才暴露。
+	if _, err := buildXizhiPushURL(key, "", ""); err != nil {
+		return nil, err
+	}
+	return &xizhiNotifier{name: notifierName(cfg, "息知"), key: key}, nil
+}
+
+func (n *xizhiNotifier) Name() string { return n.name }
+
+func (n *xizhiNotifier) Send(ctx context.Context, msg PushMessage) error {
+	pushURL, err := buildXizhiPushURL(n.key, msg.Head, msg.Body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pushURL, nil)
+	if err != nil {
+		return err
+	}
+	_, err = doPushRequest(req)
+	return err
+}
+
+func buildXizhiPushURL(pushInput string, title string, content string) (string, error) {
+	pushInput = strings.TrimSpace(pushInput)
+	if pushInput == "" {
+		return "", errors.New("推送链接/Key 不能为空")
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = "消息通知"
+	}
+	content = strings.TrimSpace(content)
+
+	// 1) 允许直接粘贴完整链接，例如：
+	// https://xizhi.qqoq.net/XZxxxx.send
+	if strings.Contains(pushInput, "://") {
+		u, err := url.Parse(pushInput)
+		if err != nil {
+			return "", err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return "", errors.New("无效推送链接")
+		}
+		q := u.Query()
+		q.Set("title", title)
+		q.Set("content", content)
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	}
+
+	// 2) 允许只填 key 或填 "XZxxxx.send"
+	key := strings.TrimSpace(pushInput)
+	if strings.Contains(key, "/") {
+		parts := strings.Split(key, "/")
+		key = strings.TrimSpace(parts[len(parts)-1])
+	}
+	key = strings.TrimSuffix(key, ".send")
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", errors.New("无效推送 key")
+	}
+
+	u := &url.URL{
+		Scheme: "https",
+		Host:   xizhiDefaultHost,
+		Path:   "/" + key + ".send",
+	}
+	q := u.Query()
+	q.Set("title", title)
+	q.Set("content", content)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	serverChanDefaultHost = "sctapi.ftqq.com"
+	pushPlusSendURL       = "https://www.pushplus.plus/send"
+	barkDefaultServer     = "https://api.day.app"
+)
+
+var (
+	// Server酱³ 的 SendKey 形如 sctp{uid}t...，推送地址按 uid 区分。
+	serverChan3KeyRe = regexp.MustCompile(`^sctp(\d+)t`)
+	pushPlusTokenRe  = regexp.MustCompile(`^[0-9a-fA-F]{32}$`)
+)
+
+// detectPushService 根据粘贴的推送链接或 Key 判断所属推送服务，无法识别时按息知处理。
+func detectPushService(input string) string {
+	input = strings.TrimSpace(input)
+	lower := strings.ToLower(input)
+
+	if strings.HasPrefix(lower, "bark:") {
+		return "bark"
+	}
+	if strings.Contains(input, "://") {
+		u, err := url.Parse(input)
+		if err != nil {
+			return "xizhi"
+		}
+		host := strings.ToLower(u.Hostname())
+		switch {
+		case host == serverChanDefaultHost || strings.HasSuffix(host, ".push.ft07.com"):
+			return "serverchan"
+		case host == "pushplus.plus" || strings.HasSuffix(host, ".pushplus.plus"):
+			return "pushplus"
+		case host == "day.app" || strings.HasSuffix(host, ".day.app"):
+			return "bark"
+		}
+		return "xizhi"
+	}
+
+	switch {
+	case strings.HasPrefix(input, "SCT") || serverChan3KeyRe.MatchString(input):
+		return "serverchan"
+	case pushPlusTokenRe.MatchString(input):
+		return "pushplus"
+	}
+	return "xizhi"
+}
+
+// newAutoNotifier 按 Target 自动识别推送服务，用于主界面的“推送链接/Key”。
+func newAutoNotifier(cfg NotifierConfig) (Notifier, error) {
+	cfg.Type = detectPushService(cfg.Target)
+	switch cfg.Type {
+	case "serverchan":
+		return newServerChanNotifier(cfg)
+	case "pushplus":
+		return newPushPlusNotifier(cfg)
+	case "bark":
+		return newBarkNotifier(cfg)
+	}
+	return newXizhiNotifier(cfg)
+}
+
+type serverChanNotifier struct {
+	name    string
+	sendURL string
+}
+
+func newServerChanNotifier(cfg NotifierConfig) (Notifier, error) {
+	target := strings.TrimSpace(cfg.Target)
+	if target == "" {
+		return nil, errors.New("Server酱 SendKey 不能为空")
+	}
+
+	sendURL := ""
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, errors.New("无效 Server酱 推送链接")
+		}
+		u.RawQuery = ""
+		sendURL = u.String()
+	} else {
+		key := strings.TrimSuffix(target, ".send")
+		if m := serverChan3KeyRe.FindStringSubmatch(key); m != nil {
+			sendURL = "https://" + m[1] + ".push.ft07.com/send/" + key + ".send"
+		} else {
+			sendURL = "https://" + serverChanDefaultHost + "/" + key + ".send"
+		}
+	}
+
+	return &serverChanNotifier{name: notifierName(cfg, "Server酱"), sendURL: sendURL}, nil
+}
+
+func (n *serverChanNotifier) Name() string { return n.name }
+
+func (n *serverChanNotifier) Send(ctx context.Context, msg PushMessage) error {
+	form := url.Values{}
+	form.Set("title", msg.Head)
+	// Server酱 的 desp 按 Markdown 渲染，单个换行会被合并，这里改成段落。
+	form.Set("desp", strings.ReplaceAll(msg.Body, "\n", "\n\n"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.sendURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := doPushRequest(req)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &result); err == nil && result.Code != 0 {
+		return errors.New("Server酱返回错误 " + strconv.Itoa(result.Code) + ": " + result.Message)
+	}
+	return nil
+}
+
+type pushPlusNotifier struct {
+	name    string
+	token   string
+	topic   string
+	sendURL string
+}
+
+func newPushPlusNotifier(cfg NotifierConfig) (Notifier, error) {
+	target := strings.TrimSpace(cfg.Target)
+	token := target
+	sendURL := pushPlusSendURL
+	topic := cfg.option("topic")
+
+	// 也允许粘贴 http://www.pushplus.plus/send?token=xxx 这样的完整链接。
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(u.Query().Get("token"))
+		if topic == "" {
+			topic = strings.TrimSpace(u.Query().Get("topic"))
+		}
+		u.RawQuery = ""
+		if u.Path == "" || u.Path == "/" {
+			u.Path = "/send"
+		}
+		sendURL = u.String()
+	}
+	if token == "" {
+		return nil, errors.New("PushPlus token 不能为空")
+	}
+
+	return &pushPlusNotifier{name: notifierName(cfg, "PushPlus"), token: token, topic: topic, sendURL: sendURL}, nil
+}
+
+func (n *pushPlusNotifier) Name() string { return n.name }
+
+func (n *pushPlusNotifier) Send(ctx context.Context, msg PushMessage) error {
+	payload := map[string]string{
+		"token":    n.token,
+		"title":    msg.Head,
+		"content":  msg.Body,
+		"template": "txt",
+	}
+	if n.topic != "" {
+		payload["topic"] = n.topic
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.sendURL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	body, err := doPushRequest(req)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &result); err == nil && result.Code != 200 {
+		return errors.New("PushPlus返回错误 " + strconv.Itoa(result.Code) + ": " + result.Msg)
+	}
+	return nil
+}
+
+type barkNotifier struct {
+	name      string
+	server    string
+	deviceKey string
+	group     string
+}
+
+func newBarkNotifier(cfg NotifierConfig) (Notifier, error) {
+	target := strings.TrimSpace(cfg.Target)
+	target = strings.TrimPrefix(strings.TrimPrefix(target, "bark:"), "//")
+	server := strings.TrimRight(cfg.option("server"), "/")
+	key := target
+
+	// 允许粘贴 Bark App 中复制的 https://api.day.app/xxxx/ 链接（可能带示例标题）。
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, errors.New("无效 Bark 推送链接")
+		}
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		key = strings.TrimSpace(parts[0])
+		if server == "" {
+			server = u.Scheme + "://" + u.Host
+		}
+	}
+	if key == "" {
+		return nil, errors.New("Bark device key 不能为空")
+	}
+	if server == "" {
+		server = barkDefaultServer
+	}
+
+	group := cfg.option("group")
+	if group == "" {
+		group = AppName
+	}
+	return &barkNotifier{name: notifierName(cfg, "Bark"), server: server, deviceKey: key, group: group}, nil
+}
+
+func (n *barkNotifier) Name() string { return n.name }
+
+func (n *barkNotifier) Send(ctx context.Context, msg PushMessage) error {
+	payload := map[string]string{
+		"device_key": n.deviceKey,
+		"title":      msg.Head,
+		"body":       msg.Body,
+		"group":      n.group,
+	}
+	if link := strings.TrimSpace(msg.Link); link != "" {
+		payload["url"] = link
+	}
+	if msg.Priority == pushPriorityHigh {
+		// 时效性通知可突破 iOS 专注模式。
+		payload["level"] = "timeSensitive"
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.server+"/push", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	body, err := doPushRequest(req)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &result); err == nil && result.Code != 200 {
+		return errors.New("Bark返回错误 " + strconv.Itoa(result.Code) + ": " + result.Message)
+	}
+	return nil
+}
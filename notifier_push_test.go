@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDetectPushService(t *testing.T) {
+	tests := []struct{ input, want string }{
+		{"XZ1234abcd", "xizhi"},
+		{"https://xizhi.qqoq.net/XZ1234abcd.send", "xizhi"},
+		{"SCT12345TAbCdEf", "serverchan"},
+		{"sctp6789tAbCdEf", "serverchan"},
+		{"https://sctapi.ftqq.com/SCT12345TAbCdEf.send", "serverchan"},
+		{"https://6789.push.ft07.com/send/sctp6789tAbCdEf.send", "serverchan"},
+		{"0123456789abcdef0123456789ABCDEF", "pushplus"},
+		{"http://www.pushplus.plus/send?token=0123456789abcdef0123456789abcdef", "pushplus"},
+		{"https://api.day.app/AbCdEf/", "bark"},
+		{"bark:AbCdEf", "bark"},
+		{"  https://push.example.com/send  ", "xizhi"},
+	}
+	for _, tt := range tests {
+		if got := detectPushService(tt.input); got != tt.want {
+			t.Errorf("detectPushService(%q) = %q, 期望 %q", tt.input, got, tt.want)
+		}
+		n, err := newAutoNotifier(NotifierConfig{Target: tt.input})
+		if err != nil {
+			t.Errorf("newAutoNotifier(%q): %v", tt.input, err)
+			continue
+		}
+		if kind := map[string]string{"息知": "xizhi", "Server酱": "serverchan", "PushPlus": "pushplus", "Bark": "bark"}[n.Name()]; kind != tt.want {
+			t.Errorf("newAutoNotifier(%q) 构造了 %s", tt.input, n.Name())
+		}
+	}
+}
+
+func TestServerChanSendURL(t *testing.T) {
+	tests := []struct{ target, want string }{
+		{"SCT12345TAbCdEf", "https://sctapi.ftqq.com/SCT12345TAbCdEf.send"},
+		{"sctp6789tAbCdEf.send", "https://6789.push.ft07.com/send/sctp6789tAbCdEf.send"},
+		{"https://sctapi.ftqq.com/SCT1.send?title=test", "https://sctapi.ftqq.com/SCT1.send"},
+	}
+	for _, tt := range tests {
+		n, err := newServerChanNotifier(NotifierConfig{Target: tt.target})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.target, err)
+		}
+		if got := n.(*serverChanNotifier).sendURL; got != tt.want {
+			t.Errorf("%s: sendURL = %s, 期望 %s", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestPushServicesSend(t *testing.T) {
+	var got *http.Request
+	var form map[string]string
+	reply := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		form = map[string]string{}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			json.NewDecoder(r.Body).Decode(&form)
+		} else {
+			r.ParseForm()
+			for k := range r.PostForm {
+				form[k] = r.PostForm.Get(k)
+			}
+		}
+		w.Write([]byte(reply))
+	}))
+	defer srv.Close()
+
+	msg := testPushMessage()
+	msg.Body = "第一行\n第二行"
+	msg.Priority = pushPriorityHigh
+
+	tests := []struct {
+		name  string
+		cfg   NotifierConfig
+		reply string
+		path  string
+		want  map[string]string
+		err   string
+	}{
+		{
+			name:  "Server酱正文按段落发送",
+			cfg:   NotifierConfig{Type: "serverchan", Target: srv.URL + "/SCT1.send"},
+			reply: `{"code":0}`,
+			path:  "/SCT1.send",
+			want:  map[string]string{"title": "天龙发公告了", "desp": "第一行\n\n第二行"},
+		},
+		{
+			name:  "Server酱返回错误码",
+			cfg:   NotifierConfig{Type: "serverchan", Target: srv.URL + "/SCT1.send"},
+			reply: `{"code":40001,"message":"bad pushkey"}`,
+			err:   "Server酱返回错误 40001: bad pushkey",
+		},
+		{
+			name:  "PushPlus 从链接读取 token 与 topic",
+			cfg:   NotifierConfig{Type: "pushplus", Target: srv.URL + "?token=tk&topic=tlbb"},
+			reply: `{"code":200}`,
+			path:  "/send",
+			want:  map[string]string{"token": "tk", "topic": "tlbb", "title": "天龙发公告了", "content": "第一行\n第二行", "template": "txt"},
+		},
+		{
+			name:  "PushPlus 返回错误码",
+			cfg:   NotifierConfig{Type: "pushplus", Target: srv.URL + "/send?token=tk"},
+			reply: `{"code":903,"msg":"无效的用户token"}`,
+			err:   "PushPlus返回错误 903: 无效的用户token",
+		},
+		{
+			name:  "Bark 高优先级与链接",
+			cfg:   NotifierConfig{Type: "bark", Target: srv.URL + "/DeviceKey/示例标题"},
+			reply: `{"code":200}`,
+			path:  "/push",
+			want: map[string]string{"device_key": "DeviceKey", "title": "天龙发公告了", "body": "第一行\n第二行",
+				"group": AppName, "url": msg.Link, "level": "timeSensitive"},
+		},
+	}
+	for _, tt := range tests {
+		n, err := newNotifier(tt.cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		reply = tt.reply
+		err = n.Send(context.Background(), msg)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("%s: err = %v, 期望 %q", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got.URL.Path != tt.path {
+			t.Errorf("%s: 请求路径 %s, 期望 %s", tt.name, got.URL.Path, tt.path)
+		}
+		for k, v := range tt.want {
+			if form[k] != v {
+				t.Errorf("%s: %s = %q, 期望 %q", tt.name, k, form[k], v)
+			}
+		}
+		if len(form) != len(tt.want) {
+			t.Errorf("%s: 多余字段 %v", tt.name, form)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dingTalkRobotURL = "https://oapi.dingtalk.com/robot/send"
+	feishuHookURL    = "https://open.feishu.cn/open-apis/bot/v2/hook/"
+	larkHookURL      = "https://open.larksuite.com/open-apis/bot/v2/hook/"
+	wecomRobotURL    = "https://qyapi.weixin.qq.com/cgi-bin/webhook/send"
+)
+
+// robotMarkdown 生成群机器人使用的 Markdown 正文：标题可点击跳转到原文。
+func robotMarkdown(msg PushMessage) string {
+	var b strings.Builder
+	title := strings.TrimSpace(msg.Title)
+	if title == "" {
+		title = "有新消息"
+	}
+	if link := strings.TrimSpace(msg.Link); link != "" {
+		b.WriteString("**[" + title + "](" + link + ")**")
+	} else {
+		b.WriteString("**" + title + "**")
+	}
+	if summary := strings.TrimSpace(msg.Summary); summary != "" {
+		b.WriteString("\n\n" + summary)
+	}
+	if source := strings.TrimSpace(msg.Source); source != "" {
+		b.WriteString("\n\n来源：" + source)
+	}
+	return b.String()
+}
+
+// ensureKeyword 处理机器人的“自定义关键词”安全设置：消息中不含任一关键词时会被拒收，
+// 因此在末尾补上第一个关键词。多个关键词用逗号分隔。
+func ensureKeyword(text string, keywords string) string {
+	var first string
+	for _, kw := range strings.FieldsFunc(keywords, func(r rune) bool { return r == ',' || r == '，' }) {
+		kw = strings.TrimSpace(kw)
+		if kw == "" {
+			continue
+		}
+		if strings.Contains(text, kw) {
+			return text
+		}
+		if first == "" {
+			first = kw
+		}
+	}
+	if first == "" {
+		return text
+	}
+	return text + "\n\n" + first
+}
+
+// hmacSHA256Base64 计算 HMAC-SHA256 并以标准 base64 编码返回。
+func hmacSHA256Base64(key string, data string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(data))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// robotWebhookURL 允许填写完整 Webhook 地址，或只填 token/key 拼接到默认地址。
+func robotWebhookURL(target string, defaultURL string, tokenParam string) (*url.URL, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, errors.New("Webhook 地址不能为空")
+	}
+	if !strings.Contains(target, "://") {
+		if tokenParam == "" {
+			target = defaultURL + target
+		} else {
+			target = defaultURL + "?" + tokenParam + "=" + url.QueryEscape(target)
+		}
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, errors.New("无效 Webhook 地址")
+	}
+	return u, nil
+}
+
+// postRobotJSON 发送 JSON 消息，并按返回的错误码判断是否成功。
+func postRobotJSON(ctx context.Context, endpoint string, payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	body, err := doPushRequest(req)
+	if err != nil {
+		return err
+	}
+
+	// 钉钉/企业微信返回 errcode/errmsg，飞书返回 code/msg（旧版为 StatusCode/StatusMessage）。
+	var result struct {
+		ErrCode    *int   `json:"errcode"`
+		ErrMsg     string `json:"errmsg"`
+		Code       *int   `json:"code"`
+		Msg        string `json:"msg"`
+		StatusCode *int   `json:"StatusCode"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil
+	}
+	switch {
+	case result.ErrCode != nil && *result.ErrCode != 0:
+		return errors.New("机器人返回错误 " + strconv.Itoa(*result.ErrCode) + ": " + result.ErrMsg)
+	case result.Code != nil && *result.Code != 0:
+		return errors.New("机器人返回错误 " + strconv.Itoa(*result.Code) + ": " + result.Msg)
+	case result.StatusCode != nil && *result.StatusCode != 0:
+		return errors.New("机器人返回错误 " + strconv.Itoa(*result.StatusCode))
+	}
+	return nil
+}
+
+type dingTalkNotifier struct {
+	name     string
+	endpoint *url.URL
+	secret   string
+	keyword  string
+	now      func() time.Time
+}
+
+func newDingTalkNotifier(cfg NotifierConfig) (Notifier, error) {
+	u, err := robotWebhookURL(cfg.Target, dingTalkRobotURL, "access_token")
+	if err != nil {
+		return nil, err
+	}
+	return &dingTalkNotifier{
+		name:     notifierName(cfg, "钉钉机器人"),
+		endpoint: u,
+		secret:   cfg.option("secret"),
+		keyword:  cfg.option("keyword"),
+		now:      time.Now,
+	}, nil
+}
+
+func (n *dingTalkNotifier) Name() string { return n.name }
+
+// signedURL 按钉钉“加签”规则追加 timestamp 与 sign 参数。
+func (n *dingTalkNotifier) signedURL() string {
+	u := *n.endpoint
+	if n.secret == "" {
+		return u.String()
+	}
+	ts := strconv.FormatInt(n.now().UnixMilli(), 10)
+	q := u.Query()
+	q.Set("timestamp", ts)
+	q.Set("sign", hmacSHA256Base64(n.secret, ts+"\n"+n.secret))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func (n *dingTalkNotifier) Send(ctx context.Context, msg PushMessage) error {
+	text := "### " + msg.Head + "\n\n" + robotMarkdown(msg)
+	payload := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": msg.Head,
+			"text":  ensureKeyword(text, n.keyword),
+		},
+	}
+	return postRobotJSON(ctx, n.signedURL(), payload)
+}
+
+type feishuNotifier struct {
+	name     string
+	endpoint string
+	secret   string
+	keyword  string
+	now      func() time.Time
+}
+
+func newFeishuNotifier(cfg NotifierConfig) (Notifier, error) {
+	return newFeishuRobot(cfg, feishuHookURL, "飞书机器人")
+}
+
+// newLarkNotifier 为国际版 Lark 机器人，消息格式与飞书相同，只填 token 时使用 larksuite.com 的地址。
+func newLarkNotifier(cfg NotifierConfig) (Notifier, error) {
+	return newFeishuRobot(cfg, larkHookURL, "Lark 机器人")
+}
+
+func newFeishuRobot(cfg NotifierConfig, hookURL string, defaultName string) (Notifier, error) {
+	u, err := robotWebhookURL(cfg.Target, hookURL, "")
+	if err != nil {
+		return nil, err
+	}
+	return &feishuNotifier{
+		name:     notifierName(cfg, defaultName),
+		endpoint: u.String(),
+		secret:   cfg.option("secret"),
+		keyword:  cfg.option("keyword"),
+		now:      time.Now,
+	}, nil
+}
+
+func (n *feishuNotifier) Name() string { return n.name }
+
+func (n *feishuNotifier) Send(ctx context.Context, msg PushMessage) error {
+	elements := []interface{}{
+		map[string]string{"tag": "markdown", "content": ensureKeyword(robotMarkdown(msg), n.keyword)},
+	}
+	if link := strings.TrimSpace(msg.Link); link != "" {
+		elements = append(elements, map[string]interface{}{
+			"tag": "action",
+			"actions": []interface{}{
+				map[string]interface{}{
+					"tag":  "button",
+					"type": "primary",
+					"url":  link,
+					"text": map[string]string{"tag": "plain_text", "content": "查看详情"},
+				},
+			},
+		})
+	}
+
+	payload := map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"header": map[string]interface{}{
+				"template": "blue",
+				"title":    map[string]string{"tag": "plain_text", "content": msg.Head},
+			},
+			"elements": elements,
+		},
+	}
+	// 飞书“签名校验”：以 timestamp+"\n"+secret 为密钥对空串做 HMAC-SHA256。
+	if n.secret != "" {
+		ts := strconv.FormatInt(n.now().Unix(), 10)
+		payload["timestamp"] = ts
+		payload["sign"] = hmacSHA256Base64(ts+"\n"+n.secret, "")
+	}
+	return postRobotJSON(ctx, n.endpoint, payload)
+}
+
+type wecomNotifier struct {
+	name     string
+	endpoint string
+	keyword  string
+}
+
+func newWecomNotifier(cfg NotifierConfig) (Notifier, error) {
+	u, err := robotWebhookURL(cfg.Target, wecomRobotURL, "key")
+	if err != nil {
+		return nil, err
+	}
+	return &wecomNotifier{
+		name:     notifierName(cfg, "企业微信机器人"),
+		endpoint: u.String(),
+		keyword:  cfg.option("keyword"),
+	}, nil
+}
+
+func (n *wecomNotifier) Name() string { return n.name }
+
+func (n *wecomNotifier) Send(ctx context.Context, msg PushMessage) error {
+	text := "### " + msg.Head + "\n" + robotMarkdown(msg)
+	payload := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"content": ensureKeyword(text, n.keyword),
+		},
+	}
+	return postRobotJSON(ctx, n.endpoint, payload)
+}
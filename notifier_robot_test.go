@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var robotTestNow = time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC)
+
+func testPushMessage() PushMessage {
+	return PushMessage{
+		Source: "公告",
+		Head:   "天龙发公告了",
+		Title:  "10月16日维护公告",
+		Link:   "http://tlhj.changyou.com/news/1.shtml",
+	}
+}
+
+func sign(key string, data string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(data))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// robotServer 把收到的请求交给 check 检查，并返回成功的 JSON 响应。
+func robotServer(t *testing.T, check func(r *http.Request, body map[string]interface{})) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		if err := json.Unmarshal(b, &body); err != nil {
+			t.Errorf("请求体不是 JSON: %v", err)
+		}
+		check(r, body)
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDingTalkSignAndKeyword(t *testing.T) {
+	const secret = "SECtest"
+	called := false
+	srv := robotServer(t, func(r *http.Request, body map[string]interface{}) {
+		called = true
+		q := r.URL.Query()
+		if q.Get("access_token") != "tok" {
+			t.Errorf("access_token = %q", q.Get("access_token"))
+		}
+		ts := q.Get("timestamp")
+		if ts != strconv.FormatInt(robotTestNow.UnixMilli(), 10) {
+			t.Errorf("timestamp = %q", ts)
+		}
+		if want := sign(secret, ts+"\n"+secret); q.Get("sign") != want {
+			t.Errorf("sign = %q, 期望 %q", q.Get("sign"), want)
+		}
+		text := body["markdown"].(map[string]interface{})["text"].(string)
+		if !strings.HasSuffix(text, "\n\n天龙") {
+			t.Errorf("未补上关键词: %q", text)
+		}
+	})
+
+	n, err := newNotifier(NotifierConfig{
+		Type:    "dingtalk",
+		Target:  srv.URL + "/robot/send?access_token=tok",
+		Options: map[string]string{"secret": secret, "keyword": "天龙,公告"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.(*dingTalkNotifier).now = func() time.Time { return robotTestNow }
+
+	// 消息中不含任一关键词时，在末尾补上第一个关键词。
+	msg := testPushMessage()
+	msg.Head = "新消息"
+	msg.Title = "维护说明"
+	msg.Source = "官网"
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if !called {
+		t.Fatal("未收到请求")
+	}
+}
+
+func TestFeishuSignAndKeyword(t *testing.T) {
+	const secret = "feishu-secret"
+	srv := robotServer(t, func(r *http.Request, body map[string]interface{}) {
+		ts, _ := body["timestamp"].(string)
+		if ts != strconv.FormatInt(robotTestNow.Unix(), 10) {
+			t.Errorf("timestamp = %q", ts)
+		}
+		if want := sign(ts+"\n"+secret, ""); body["sign"] != want {
+			t.Errorf("sign = %v, 期望 %q", body["sign"], want)
+		}
+		elements := body["card"].(map[string]interface{})["elements"].([]interface{})
+		content := elements[0].(map[string]interface{})["content"].(string)
+		if !strings.HasSuffix(content, "\n\n更新") {
+			t.Errorf("未补上关键词: %q", content)
+		}
+	})
+
+	n, err := newNotifier(NotifierConfig{
+		Type:    "feishu",
+		Target:  srv.URL + "/open-apis/bot/v2/hook/abc",
+		Options: map[string]string{"secret": secret, "keyword": "更新"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.(*feishuNotifier).now = func() time.Time { return robotTestNow }
+	if err := n.Send(context.Background(), testPushMessage()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRobotErrorCode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errcode":310000,"errmsg":"keywords not in content"}`))
+	}))
+	defer srv.Close()
+
+	n, err := newNotifier(NotifierConfig{Type: "wecom", Target: srv.URL + "/send?key=k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = n.Send(context.Background(), testPushMessage())
+	if err == nil || !strings.Contains(err.Error(), "310000") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestEnsureKeyword(t *testing.T) {
+	tests := []struct {
+		text, keywords, want string
+	}{
+		{"天龙发公告了", "", "天龙发公告了"},
+		{"天龙发公告了", "公告", "天龙发公告了"},
+		{"有新消息", "天龙，公告", "有新消息\n\n天龙"},
+		{"有新公告", " , 天龙 ,公告", "有新公告"},
+	}
+	for _, tt := range tests {
+		if got := ensureKeyword(tt.text, tt.keywords); got != tt.want {
+			t.Errorf("ensureKeyword(%q, %q) = %q, 期望 %q", tt.text, tt.keywords, got, tt.want)
+		}
+	}
+}
+
+func TestFeishuAndLarkHookURL(t *testing.T) {
+	tests := []struct {
+		typ, target, want string
+	}{
+		{"feishu", "abc-123", "https://open.feishu.cn/open-apis/bot/v2/hook/abc-123"},
+		{"lark", "abc-123", "https://open.larksuite.com/open-apis/bot/v2/hook/abc-123"},
+		{"lark", "https://open.larksuite.com/open-apis/bot/v2/hook/xyz", "https://open.larksuite.com/open-apis/bot/v2/hook/xyz"},
+	}
+	for _, tt := range tests {
+		n, err := newNotifier(NotifierConfig{Type: tt.typ, Target: tt.target})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := n.(*feishuNotifier).endpoint; got != tt.want {
+			t.Errorf("%s %q: endpoint = %q, 期望 %q", tt.typ, tt.target, got, tt.want)
+		}
+	}
+}
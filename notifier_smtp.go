@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"html"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const smtpTimeout = 30 * time.Second
+
+// smtpNotifier 通过 SMTP 发送邮件，同时附带纯文本与 HTML 两种正文。
+// security 取值：tls（隐式 TLS，常见于 465 端口）、starttls、none。
+type smtpNotifier struct {
+	name     string
+	host     string
+	port     string
+	security string
+	username string
+	password string
+	from     *mail.Address
+	to       []*mail.Address
+}
+
+func newSMTPNotifier(cfg NotifierConfig) (Notifier, error) {
+	server := strings.TrimSpace(cfg.Target)
+	if server == "" {
+		return nil, errors.New("SMTP 服务器不能为空")
+	}
+
+	host, port, err := net.SplitHostPort(server)
+	if err != nil {
+		host, port = server, ""
+	}
+	if p := cfg.option("port"); p != "" {
+		port = p
+	}
+
+	security := strings.ToLower(cfg.option("security"))
+	switch security {
+	case "":
+		if port == "465" {
+			security = "tls"
+		} else {
+			security = "starttls"
+		}
+	case "tls", "ssl":
+		security = "tls"
+	case "starttls", "none":
+	default:
+		return nil, errors.New("无效 SMTP 加密方式: " + security)
+	}
+	if port == "" {
+		switch security {
+		case "tls":
+			port = "465"
+		case "starttls":
+			port = "587"
+		default:
+			port = "25"
+		}
+	}
+	if _, err := strconv.Atoi(port); err != nil {
+		return nil, errors.New("无效 SMTP 端口: " + port)
+	}
+
+	username := cfg.option("username")
+	fromText := cfg.option("from")
+	if fromText == "" {
+		fromText = username
+	}
+	from, err := mail.ParseAddress(fromText)
+	if err != nil {
+		return nil, errors.New("无效发件人地址: " + fromText)
+	}
+	if from.Name == "" {
+		from.Name = AppName
+	}
+
+	toText := cfg.option("to")
+	if toText == "" {
+		return nil, errors.New("收件人不能为空")
+	}
+	to, err := mail.ParseAddressList(strings.ReplaceAll(strings.ReplaceAll(toText, "；", ","), ";", ","))
+	if err != nil {
+		return nil, errors.New("无效收件人地址: " + err.Error())
+	}
+
+	return &smtpNotifier{
+		name:     notifierName(cfg, "邮件"),
+		host:     host,
+		port:     port,
+		security: security,
+		username: username,
+		password: cfg.Options["password"],
+		from:     from,
+		to:       to,
+	}, nil
+}
+
+func (n *smtpNotifier) Name() string { return n.name }
+
+func (n *smtpNotifier) Send(ctx context.Context, msg PushMessage) error {
+	data, err := n.buildMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(n.host, n.port)
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	var conn net.Conn
+	if n.security == "tls" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: n.host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(smtpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if n.security == "starttls" {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP 服务器不支持 STARTTLS")
+		}
+		if err := c.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return err
+		}
+	}
+	if n.username != "" {
+		// 配置了用户名却不认证，邮件多半会被拒收或被当作垃圾邮件，直接报错。
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("SMTP 服务器未提供 AUTH 认证，请检查加密方式与端口")
+		}
+		if err := c.Auth(smtp.PlainAuth("", n.username, n.password, n.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(n.from.Address); err != nil {
+		return err
+	}
+	for _, rcpt := range n.to {
+		if err := c.Rcpt(rcpt.Address); err != nil {
+			return errors.New(rcpt.Address + ": " + err.Error())
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// buildMessage 生成 multipart/alternative 邮件，正文分别为纯文本与 HTML。
+func (n *smtpNotifier) buildMessage(msg PushMessage) ([]byte, error) {
+	subject := msg.Head
+	if title := strings.TrimSpace(msg.Title); title != "" {
+		subject += "：" + title
+	}
+
+	to := make([]string, 0, len(n.to))
+	for _, a := range n.to {
+		to = append(to, a.String())
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	if err := writeQuotedPrintablePart(mw, "text/plain; charset=UTF-8", msg.Body); err != nil {
+		return nil, err
+	}
+	if err := writeQuotedPrintablePart(mw, "text/html; charset=UTF-8", emailHTML(msg)); err != nil {
+		return nil, err
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	detectedAt := msg.DetectedAt
+	if detectedAt.IsZero() {
+		detectedAt = time.Now()
+	}
+
+	var out bytes.Buffer
+	header := []string{
+		"From: " + n.from.String(),
+		"To: " + strings.Join(to, ", "),
+		"Subject: " + mime.BEncoding.Encode("UTF-8", subject),
+		"Date: " + detectedAt.Format(time.RFC1123Z),
+		"Message-ID: " + newMessageID(n.from.Address),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + mw.Boundary(),
+	}
+	if msg.Priority == pushPriorityHigh {
+		header = append(header, "X-Priority: 1", "Importance: high")
+	}
+	for _, line := range header {
+		out.WriteString(line + "\r\n")
+	}
+	out.WriteString("\r\n")
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+func writeQuotedPrintablePart(mw *multipart.Writer, contentType string, content string) error {
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Transfer-Encoding", "quoted-printable")
+	pw, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	qw := quotedprintable.NewWriter(pw)
+	if _, err := qw.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qw.Close()
+}
+
+func emailHTML(msg PushMessage) string {
+	title := strings.TrimSpace(msg.Title)
+	if title == "" {
+		title = "有新消息"
+	}
+
+	var b strings.Builder
+	b.WriteString("<html><body>\n")
+	b.WriteString("<h3>" + html.EscapeString(msg.Head) + "</h3>\n")
+	if link := strings.TrimSpace(msg.Link); link != "" {
+		b.WriteString(`<p><a href="` + html.EscapeString(link) + `">` + html.EscapeString(title) + "</a></p>\n")
+	} else {
+		b.WriteString("<p>" + html.EscapeString(title) + "</p>\n")
+	}
+	if summary := strings.TrimSpace(msg.Summary); summary != "" {
+		b.WriteString("<p>" + html.EscapeString(summary) + "</p>\n")
+	}
+	if source := strings.TrimSpace(msg.Source); source != "" {
+		b.WriteString("<p>来源：" + html.EscapeString(source) + "</p>\n")
+	}
+	if !msg.DetectedAt.IsZero() {
+		b.WriteString("<p>检测时间：" + msg.DetectedAt.Format("2006-01-02 15:04:05") + "</p>\n")
+	}
+	b.WriteString("</body></html>\n")
+	return b.String()
+}
+
+func newMessageID(from string) string {
+	domain := "localhost"
+	if i := strings.LastIndex(from, "@"); i >= 0 && i+1 < len(from) {
+		domain = from[i+1:]
+	}
+	buf := make([]byte, 12)
+	_, _ = rand.Read(buf)
+	return "<" + hex.EncodeToString(buf) + "@" + domain + ">"
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSMTP 是一个只处理单个连接的最小 SMTP 服务器，记录收到的命令与邮件内容。
+type fakeSMTP struct {
+	addr string
+	auth bool
+
+	mu       sync.Mutex
+	commands []string
+	data     string
+	done     chan struct{}
+}
+
+func startFakeSMTP(t *testing.T, auth bool) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSMTP{addr: ln.Addr().String(), auth: auth, done: make(chan struct{})}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		defer close(s.done)
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		s.serve(textproto.NewConn(conn))
+	}()
+	return s
+}
+
+func (s *fakeSMTP) serve(c *textproto.Conn) {
+	c.PrintfLine("220 fake ESMTP")
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.commands = append(s.commands, line)
+		s.mu.Unlock()
+
+		verb := strings.ToUpper(strings.Fields(line + " ")[0])
+		switch verb {
+		case "EHLO", "HELO":
+			if s.auth {
+				c.PrintfLine("250-fake")
+				c.PrintfLine("250 AUTH PLAIN")
+			} else {
+				c.PrintfLine("250 fake")
+			}
+		case "AUTH":
+			c.PrintfLine("235 ok")
+		case "MAIL", "RCPT", "RSET", "NOOP":
+			c.PrintfLine("250 ok")
+		case "DATA":
+			c.PrintfLine("354 go ahead")
+			b, err := io.ReadAll(c.DotReader())
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = string(b)
+			s.mu.Unlock()
+			c.PrintfLine("250 queued")
+		case "QUIT":
+			c.PrintfLine("221 bye")
+			return
+		default:
+			c.PrintfLine("502 unknown")
+		}
+	}
+}
+
+func (s *fakeSMTP) commandsWithPrefix(prefix string) []string {
+	<-s.done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	for _, c := range s.commands {
+		if strings.HasPrefix(strings.ToUpper(c), prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func newTestSMTPNotifier(t *testing.T, addr string, username string) Notifier {
+	t.Helper()
+	n, err := newNotifier(NotifierConfig{
+		Type:   "smtp",
+		Target: addr,
+		Options: map[string]string{
+			"security": "none",
+			"username": username,
+			"password": "secret",
+			"from":     "bot@example.com",
+			"to":       "a@example.com；b@example.com",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestSMTPMultipartAndRecipients(t *testing.T) {
+	srv := startFakeSMTP(t, true)
+	n := newTestSMTPNotifier(t, srv.addr, "bot@example.com")
+
+	msg := testPushMessage()
+	msg.Body = "10月16日维护公告\n" + msg.Link
+	msg.Priority = pushPriorityHigh
+	if err := n.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if auth := srv.commandsWithPrefix("AUTH"); len(auth) != 1 {
+		t.Fatalf("AUTH 命令 = %v", auth)
+	}
+	rcpts := srv.commandsWithPrefix("RCPT")
+	if len(rcpts) != 2 || !strings.Contains(rcpts[0], "a@example.com") || !strings.Contains(rcpts[1], "b@example.com") {
+		t.Fatalf("RCPT 命令 = %v", rcpts)
+	}
+
+	srv.mu.Lock()
+	data := srv.data
+	srv.mu.Unlock()
+	m, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Header.Get("X-Priority"); got != "1" {
+		t.Errorf("X-Priority = %q", got)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if subject != "天龙发公告了：10月16日维护公告" {
+		t.Errorf("Subject = %q", subject)
+	}
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q (%v)", m.Header.Get("Content-Type"), err)
+	}
+
+	parts := map[string]string{}
+	mr := multipart.NewReader(m.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(p)
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		parts[ct] = string(b)
+	}
+	if parts["text/plain"] != msg.Body {
+		t.Errorf("纯文本正文 = %q", parts["text/plain"])
+	}
+	if !strings.Contains(parts["text/html"], `<a href="`+msg.Link+`">10月16日维护公告</a>`) {
+		t.Errorf("HTML 正文 = %q", parts["text/html"])
+	}
+}
+
+func TestSMTPRequiresAuthWhenUsernameSet(t *testing.T) {
+	srv := startFakeSMTP(t, false)
+	n := newTestSMTPNotifier(t, srv.addr, "bot@example.com")
+
+	err := n.Send(context.Background(), testPushMessage())
+	if err == nil || !strings.Contains(err.Error(), "AUTH") {
+		t.Fatalf("err = %v", err)
+	}
+	if mails := srv.commandsWithPrefix("MAIL"); len(mails) != 0 {
+		t.Fatalf("未认证时不应发送: %v", mails)
+	}
+}
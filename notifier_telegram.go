@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"html"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	telegramDefaultAPIBase = "https://api.telegram.org"
+
+	// telegramMaxAttempts 为遇到 429 限流时的最大尝试次数。
+	telegramMaxAttempts = 3
+	// telegramMaxRetryWait 为愿意等待的最长 retry_after，超过则直接报错交给上层处理。
+	telegramMaxRetryWait = 60 * time.Second
+)
+
+type telegramNotifier struct {
+	name    string
+	apiBase string
+	token   string
+	chatIDs []string
+}
+
+// telegramAPIError 为 Bot API 返回 ok=false 时的错误信息。
+type telegramAPIError struct {
+	Code        int
+	Description string
+	RetryAfter  time.Duration
+}
+
+func (e *telegramAPIError) Error() string {
+	msg := "Telegram返回错误 " + strconv.Itoa(e.Code) + ": " + e.Description
+	if e.RetryAfter > 0 {
+		msg += "（retry_after=" + e.RetryAfter.String() + "）"
+	}
+	return msg
+}
+
+func newTelegramNotifier(cfg NotifierConfig) (Notifier, error) {
+	token := strings.TrimSpace(cfg.Target)
+	token = strings.TrimPrefix(token, "bot")
+	if token == "" {
+		return nil, errors.New("Telegram Bot Token 不能为空")
+	}
+
+	var chatIDs []string
+	for _, id := range strings.FieldsFunc(cfg.option("chatIds"), func(r rune) bool {
+		return r == ',' || r == '，' || r == ' ' || r == '\n'
+	}) {
+		if id = strings.TrimSpace(id); id != "" {
+			chatIDs = append(chatIDs, id)
+		}
+	}
+	if len(chatIDs) == 0 {
+		return nil, errors.New("Telegram chat ID 不能为空")
+	}
+
+	// 可指向自建 Bot API 服务器（telegram-bot-api --local）或其他兼容地址。
+	apiBase := strings.TrimRight(cfg.option("apiBase"), "/")
+	if apiBase == "" {
+		apiBase = telegramDefaultAPIBase
+	}
+
+	return &telegramNotifier{
+		name:    notifierName(cfg, "Telegram"),
+		apiBase: apiBase,
+		token:   token,
+		chatIDs: chatIDs,
+	}, nil
+}
+
+func (n *telegramNotifier) Name() string { return n.name }
+
+// telegramHTML 生成 parse_mode=HTML 的消息正文。
+func telegramHTML(msg PushMessage) string {
+	var b strings.Builder
+	b.WriteString("<b>" + html.EscapeString(msg.Head) + "</b>\n")
+
+	title := strings.TrimSpace(msg.Title)
+	if title == "" {
+		title = "有新消息"
+	}
+	if link := strings.TrimSpace(msg.Link); link != "" {
+		b.WriteString(`<a href="` + html.EscapeString(link) + `">` + html.EscapeString(title) + "</a>")
+	} else {
+		b.WriteString(html.EscapeString(title))
+	}
+	if summary := strings.TrimSpace(msg.Summary); summary != "" {
+		b.WriteString("\n" + html.EscapeString(summary))
+	}
+	if source := strings.TrimSpace(msg.Source); source != "" {
+		b.WriteString("\n来源：" + html.EscapeString(source))
+	}
+	return b.String()
+}
+
+// Send 逐个发送到各 chat，msg.Recipients 非空时只发送到其中仍在配置里的 chat。
+// 部分 chat 失败时返回 *partialSendError，重试时只发给失败的 chat，避免已收到的 chat 重复收到。
+func (n *telegramNotifier) Send(ctx context.Context, msg PushMessage) error {
+	text := telegramHTML(msg)
+
+	var errs []error
+	var failed []string
+	var retryAfter time.Duration
+	for _, chatID := range n.chatIDs {
+		if len(msg.Recipients) > 0 && !slices.Contains(msg.Recipients, chatID) {
+			continue
+		}
+		if err := n.sendWithRetry(ctx, chatID, text); err != nil {
+			errs = append(errs, errors.New("chat "+chatID+": "+err.Error()))
+			failed = append(failed, chatID)
+			var apiErr *telegramAPIError
+			if errors.As(err, &apiErr) {
+				retryAfter = max(retryAfter, apiErr.RetryAfter)
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &partialSendError{Recipients: failed, RetryAfter: retryAfter, Err: errors.Join(errs...)}
+}
+
+// sendWithRetry 在遇到 429 限流时按 retry_after 等待后重试。
+func (n *telegramNotifier) sendWithRetry(ctx context.Context, chatID string, text string) error {
+	var err error
+	for attempt := 1; attempt <= telegramMaxAttempts; attempt++ {
+		err = n.sendMessage(ctx, chatID, text)
+
+		var apiErr *telegramAPIError
+		if !errors.As(err, &apiErr) || apiErr.RetryAfter <= 0 || apiErr.RetryAfter > telegramMaxRetryWait {
+			return err
+		}
+		if attempt == telegramMaxAttempts {
+			break
+		}
+
+		t := time.NewTimer(apiErr.RetryAfter)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	return err
+}
+
+func (n *telegramNotifier) sendMessage(ctx context.Context, chatID string, text string) error {
+	payload := map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": false,
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	endpoint := n.apiBase + "/bot" + n.token + "/sendMessage"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := pushHTTPClient.Do(req)
+	if err != nil {
+		// 错误信息中可能带有完整 URL，避免把 Bot Token 写进日志。
+		return errors.New(strings.ReplaceAll(err.Error(), n.token, "***"))
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		ErrorCode   int    `json:"error_code"`
+		Description string `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if jsonErr := json.Unmarshal(body, &result); jsonErr != nil {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return errors.New("HTTP " + resp.Status + ": " + strings.TrimSpace(string(body)))
+		}
+		return jsonErr
+	}
+	if !result.OK {
+		code := result.ErrorCode
+		if code == 0 {
+			code = resp.StatusCode
+		}
+		return &telegramAPIError{
+			Code:        code,
+			Description: result.Description,
+			RetryAfter:  time.Duration(result.Parameters.RetryAfter) * time.Second,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTelegramRetriesOnlyFailedChats(t *testing.T) {
+	useTempConfigDir(t)
+
+	var mu sync.Mutex
+	var chats []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			ChatID string `json:"chat_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		mu.Lock()
+		chats = append(chats, payload.ChatID)
+		mu.Unlock()
+		if payload.ChatID == "2" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":300}}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	n, err := newNotifier(NotifierConfig{
+		Type:    "telegram",
+		Target:  "123:abc",
+		Options: map[string]string{"chatIds": "1, 2, 3", "apiBase": srv.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := testPushMessage()
+	sendErr := n.Send(context.Background(), msg)
+	if sendErr == nil {
+		t.Fatal("chat 2 限流时应返回错误")
+	}
+
+	m := NewMonitor()
+	before := time.Now()
+	m.enqueueOutbox(n.Name(), msg, sendErr)
+	e := m.outbox.Pending[0]
+	if !slices.Equal(e.Message.Recipients, []string{"2"}) {
+		t.Fatalf("Recipients = %v", e.Message.Recipients)
+	}
+	if e.NextAttempt.Before(before.Add(300 * time.Second)) {
+		t.Fatalf("NextAttempt = %v，未遵守 retry_after", e.NextAttempt)
+	}
+
+	mu.Lock()
+	chats = nil
+	mu.Unlock()
+	n.Send(context.Background(), e.Message)
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(chats, []string{"2"}) {
+		t.Fatalf("重试发送到 %v，期望只发送到 chat 2", chats)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordNotifier 记录收到的消息，err 非空时每次发送都返回该错误。
+type recordNotifier struct {
+	name string
+	err  error
+
+	mu   sync.Mutex
+	sent []PushMessage
+}
+
+func (n *recordNotifier) Name() string { return n.name }
+
+func (n *recordNotifier) Send(ctx context.Context, msg PushMessage) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, msg)
+	return n.err
+}
+
+func (n *recordNotifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.sent)
+}
+
+func TestNewNotifier(t *testing.T) {
+	tests := []struct {
+		cfg  NotifierConfig
+		name string
+		err  string
+	}{
+		{NotifierConfig{Type: "xizhi", Target: "XZabc"}, "息知", ""},
+		{NotifierConfig{Type: " Xizhi ", Name: "备用", Target: "XZabc"}, "备用", ""},
+		{NotifierConfig{Type: "xizhi"}, "", "推送链接/Key 不能为空"},
+		{NotifierConfig{Type: "gotify", Target: "x"}, "", "未知推送渠道类型: gotify"},
+	}
+	for _, tt := range tests {
+		n, err := newNotifier(tt.cfg)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("%+v: err = %v, 期望 %q", tt.cfg, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || n.Name() != tt.name {
+			t.Errorf("%+v: 名称 %v, err %v, 期望 %q", tt.cfg, n, err, tt.name)
+		}
+	}
+}
+
+func TestBuildXizhiPushURL(t *testing.T) {
+	tests := []struct{ input, want string }{
+		{"XZabc", "https://xizhi.qqoq.net/XZabc.send"},
+		{"XZabc.send", "https://xizhi.qqoq.net/XZabc.send"},
+		{"xizhi.qqoq.net/XZabc.send", "https://xizhi.qqoq.net/XZabc.send"},
+		{"https://xizhi.qqoq.net/XZabc.send?from=copy", "https://xizhi.qqoq.net/XZabc.send"},
+	}
+	for _, tt := range tests {
+		got, err := buildXizhiPushURL(tt.input, "", "正文")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.input, err)
+		}
+		u, _ := url.Parse(got)
+		q := u.Query()
+		u.RawQuery = ""
+		if u.String() != tt.want || q.Get("title") != "消息通知" || q.Get("content") != "正文" {
+			t.Errorf("%s: %s, 期望 %s", tt.input, got, tt.want)
+		}
+	}
+	if _, err := buildXizhiPushURL("https:///XZabc.send", "", ""); err == nil {
+		t.Error("缺少主机的链接应报错")
+	}
+}
+
+func TestBuildNotifiersSkipsBrokenChannels(t *testing.T) {
+	cfgs := []NotifierConfig{
+		{Type: "xizhi", Name: "息知", Enabled: true, Target: "XZabc"},
+		{Type: "gotify", Name: "自建", Enabled: true, Target: "x"},
+		{Type: "xizhi", Name: "停用", Enabled: false},
+		{Type: "bark", Enabled: true, Target: "https://api.day.app/key1"},
+	}
+	notifiers, errs := buildNotifiers("", cfgs)
+	var names []string
+	for _, n := range notifiers {
+		names = append(names, n.Name())
+	}
+	if strings.Join(names, ",") != "息知,Bark" {
+		t.Errorf("渠道 = %v", names)
+	}
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "自建: 未知推送渠道类型") {
+		t.Errorf("errs = %v", errs)
+	}
+}
+
+func TestPushAllSendsToEveryNotifier(t *testing.T) {
+	useTempConfigDir(t)
+
+	m := NewMonitor()
+	ok1 := &recordNotifier{name: "甲"}
+	failing := &recordNotifier{name: "乙", err: errors.New("HTTP 500")}
+	ok2 := &recordNotifier{name: "丙"}
+	m.pushAll(context.Background(), nil, []Notifier{ok1, failing, ok2}, testPushMessage())
+
+	for _, n := range []*recordNotifier{ok1, failing, ok2} {
+		if n.count() != 1 {
+			t.Errorf("%s 收到 %d 条, 期望 1 条", n.name, n.count())
+		}
+	}
+}
+
+func TestBuildNotifiersRejectsDuplicateNames(t *testing.T) {
+	cfgs := []NotifierConfig{
+		{Type: "bark", Enabled: true, Target: "https://api.day.app/key1"},
+		{Type: "bark", Enabled: true, Target: "https://api.day.app/key2"},
+		{Type: "bark", Name: "Bark 备用", Enabled: true, Target: "https://api.day.app/key3"},
+		{Type: "bark", Enabled: false, Target: "https://api.day.app/key4"},
+	}
+	notifiers, errs := buildNotifiers("", cfgs)
+	if len(notifiers) != 2 {
+		t.Fatalf("渠道数 = %d, 期望 2", len(notifiers))
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "名称重复") {
+		t.Fatalf("errs = %v", errs)
+	}
+
+	// 主界面的推送 Key 同样占用默认名称。
+	_, errs = buildNotifiers("https://api.day.app/key0", cfgs[:1])
+	if len(errs) != 1 {
+		t.Fatalf("errs = %v", errs)
+	}
+}
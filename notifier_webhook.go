@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// webhookDefaultBody 为未填写正文模板时使用的 JSON 正文。
+const webhookDefaultBody = `{"source":{{json .Source}},"head":{{json .Head}},"title":{{json .Title}},"link":{{json .Link}},"detectedAt":{{json .DetectedAt}}}`
+
+// webhookTemplateFuncs 为 Webhook 模板提供的辅助函数：
+// json 输出带引号的 JSON 值，urlquery 为内置函数，可用于拼接查询参数。
+var webhookTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// webhookNotifier 为自定义 Webhook：URL 与正文都是 text/template 模板，
+// 可使用 .Source .Head .Title .Link .DetectedAt。
+type webhookNotifier struct {
+	name    string
+	method  string
+	url     *template.Template
+	body    *template.Template
+	headers map[string]string
+}
+
+// NotifierConfig.Options 中 method 为请求方法（默认 POST），body 为正文模板；
+// 请求头放在 NotifierConfig.Headers 中。
+func newWebhookNotifier(cfg NotifierConfig) (Notifier, error) {
+	rawURL := strings.TrimSpace(cfg.Target)
+	if rawURL == "" {
+		return nil, errors.New("Webhook 地址不能为空")
+	}
+	urlTmpl, err := template.New("url").Funcs(webhookTemplateFuncs).Parse(rawURL)
+	if err != nil {
+		return nil, errors.New("Webhook 地址模板有误: " + err.Error())
+	}
+
+	method := strings.ToUpper(cfg.option("method"))
+	if method == "" {
+		method = http.MethodPost
+	}
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return nil, errors.New("不支持的请求方法: " + method)
+	}
+
+	var bodyTmpl *template.Template
+	if method != http.MethodGet {
+		rawBody := cfg.option("body")
+		if rawBody == "" {
+			rawBody = webhookDefaultBody
+		}
+		bodyTmpl, err = template.New("body").Funcs(webhookTemplateFuncs).Parse(rawBody)
+		if err != nil {
+			return nil, errors.New("正文模板有误: " + err.Error())
+		}
+	}
+
+	headers := map[string]string{}
+	for k, v := range cfg.Headers {
+		if k = strings.TrimSpace(k); k != "" {
+			headers[k] = strings.TrimSpace(v)
+		}
+	}
+
+	n := &webhookNotifier{
+		name:    notifierName(cfg, "自定义Webhook"),
+		method:  method,
+		url:     urlTmpl,
+		body:    bodyTmpl,
+		headers: headers,
+	}
+
+	// 用示例消息渲染一次，提前暴露模板字段写错、JSON 格式不合法等问题。
+	if _, err := n.newRequest(context.Background(), samplePushMessage()); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (n *webhookNotifier) Name() string { return n.name }
+
+func (n *webhookNotifier) newRequest(ctx context.Context, msg PushMessage) (*http.Request, error) {
+	var urlBuf bytes.Buffer
+	if err := n.url.Execute(&urlBuf, msg); err != nil {
+		return nil, errors.New("Webhook 地址模板渲染失败: " + err.Error())
+	}
+	u, err := url.Parse(strings.TrimSpace(urlBuf.String()))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, errors.New("无效 Webhook 地址")
+	}
+
+	var body bytes.Buffer
+	if n.body != nil {
+		if err := n.body.Execute(&body, msg); err != nil {
+			return nil, errors.New("正文模板渲染失败: " + err.Error())
+		}
+	}
+
+	contentType := ""
+	for k, v := range n.headers {
+		if strings.EqualFold(k, "Content-Type") {
+			contentType = v
+		}
+	}
+	if n.body != nil && contentType == "" {
+		contentType = "application/json; charset=utf-8"
+	}
+	if strings.Contains(strings.ToLower(contentType), "json") && !json.Valid(body.Bytes()) {
+		return nil, errors.New("正文模板渲染结果不是合法 JSON")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, n.method, u.String(), &body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range n.headers {
+		req.Header.Set(k, v)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
+
+func (n *webhookNotifier) Send(ctx context.Context, msg PushMessage) error {
+	req, err := n.newRequest(ctx, msg)
+	if err != nil {
+		return err
+	}
+	_, err = doPushRequest(req)
+	return err
+}
+
+// samplePushMessage 为测试推送与模板校验使用的示例消息。
+func samplePushMessage() PushMessage {
+	title := "这是一条测试推送"
+	link := "http://tlhj.changyou.com/"
+	now := time.Now()
+	_, body, _ := PushTemplate{Title: AppName, Body: defaultPushBodyTemplate}.render(newPushTemplateData("测试", title, link, nil, now))
+	return PushMessage{
+		Source:     "测试",
+		Head:       AppName,
+		Title:      title,
+		Link:       link,
+		Body:       body,
+		DetectedAt: now,
+	}
+}
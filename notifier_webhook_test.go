@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookSend(t *testing.T) {
+	var got *http.Request
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got, body = r, string(b)
+	}))
+	defer srv.Close()
+
+	msg := testPushMessage()
+	msg.Title = `含 "引号" 与 & 的标题`
+	msg.DetectedAt = robotTestNow
+
+	tests := []struct {
+		name     string
+		cfg      NotifierConfig
+		method   string
+		query    string
+		body     string
+		ctype    string
+		headerOK func(http.Header) bool
+	}{
+		{
+			name:   "默认正文",
+			cfg:    NotifierConfig{Target: srv.URL + "/hook"},
+			method: http.MethodPost,
+			body:   `{"source":"公告","head":"天龙发公告了","title":"含 \"引号\" 与 \u0026 的标题","link":"http://tlhj.changyou.com/news/1.shtml","detectedAt":"2026-10-16T20:00:00Z"}`,
+			ctype:  "application/json; charset=utf-8",
+		},
+		{
+			name:   "GET 把标题拼进查询参数",
+			cfg:    NotifierConfig{Target: srv.URL + "/hook?t={{urlquery .Title}}", Options: map[string]string{"method": "get"}},
+			method: http.MethodGet,
+			query:  "t=" + msg.Title,
+		},
+		{
+			name: "自定义正文与请求头",
+			cfg: NotifierConfig{
+				Target:  srv.URL + "/hook",
+				Options: map[string]string{"method": "PUT", "body": "{{.Head}}: {{.Title}}"},
+				Headers: map[string]string{"content-type": "text/plain", " Authorization ": "Bearer x"},
+			},
+			method:   http.MethodPut,
+			body:     "天龙发公告了: " + msg.Title,
+			ctype:    "text/plain",
+			headerOK: func(h http.Header) bool { return h.Get("Authorization") == "Bearer x" },
+		},
+	}
+	for _, tt := range tests {
+		tt.cfg.Type = "webhook"
+		n, err := newNotifier(tt.cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if err := n.Send(context.Background(), msg); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got.Method != tt.method || got.URL.Path != "/hook" || body != tt.body || got.Header.Get("Content-Type") != tt.ctype {
+			t.Errorf("%s: %s %s %q Content-Type=%q", tt.name, got.Method, got.URL, body, got.Header.Get("Content-Type"))
+		}
+		if tt.query != "" && got.URL.Query().Get("t") != msg.Title {
+			t.Errorf("%s: 查询参数 %s", tt.name, got.URL.RawQuery)
+		}
+		if tt.headerOK != nil && !tt.headerOK(got.Header) {
+			t.Errorf("%s: 请求头 %v", tt.name, got.Header)
+		}
+	}
+}
+
+func TestWebhookRejectsBadConfig(t *testing.T) {
+	tests := []struct {
+		cfg  NotifierConfig
+		want string
+	}{
+		{NotifierConfig{}, "Webhook 地址不能为空"},
+		{NotifierConfig{Target: "https://example.com/{{.Title"}, "Webhook 地址模板有误"},
+		{NotifierConfig{Target: "/relative/{{.Title}}"}, "无效 Webhook 地址"},
+		{NotifierConfig{Target: "https://example.com/", Options: map[string]string{"method": "DELETE"}}, "不支持的请求方法: DELETE"},
+		{NotifierConfig{Target: "https://example.com/", Options: map[string]string{"body": "{{.Titel}}"}}, "正文模板渲染失败"},
+		{NotifierConfig{Target: "https://example.com/", Options: map[string]string{"body": `{"title": {{.Title}}}`}}, "正文模板渲染结果不是合法 JSON"},
+	}
+	for _, tt := range tests {
+		tt.cfg.Type = "webhook"
+		if _, err := newNotifier(tt.cfg); err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("%+v: err = %v, 期望 %q", tt.cfg, err, tt.want)
+		}
+	}
+}
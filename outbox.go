@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	outboxFileName = "outbox.json"
+
+	// outboxMaxAttempts 为单条推送的最大发送次数（含首次），超过后转入死信。
+	outboxMaxAttempts = 8
+	outboxBaseDelay   = time.Minute
+	outboxMaxDelay    = 2 * time.Hour
+	// outboxMaxDead 为保留的死信条数上限，超出时丢弃最旧的。
+	outboxMaxDead = 100
+	// outboxRetryTick 为检查是否有到期失败推送的间隔。
+	outboxRetryTick = 30 * time.Second
+)
+
+// outboxEntry 是一条发送失败、等待重试的推送，只针对失败的那个渠道。
+type outboxEntry struct {
+	ID          string      `json:"id"`
+	Notifier    string      `json:"notifier"`
+	Message     PushMessage `json:"message"`
+	Attempts    int         `json:"attempts"`
+	NextAttempt time.Time   `json:"nextAttempt"`
+	LastError   string      `json:"lastError"`
+	CreatedAt   time.Time   `json:"createdAt"`
+}
+
+// outboxState 为持久化的发件箱：Pending 等待重试，Dead 为超过重试上限的死信。
+type outboxState struct {
+	Pending []outboxEntry `json:"pending"`
+	Dead    []outboxEntry `json:"dead"`
+}
+
+func outboxFilePath() (string, error) {
+	path, err := settingsFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), outboxFileName), nil
+}
+
+func loadOutbox() (outboxState, error) {
+	path, err := outboxFilePath()
+	if err != nil {
+		return outboxState{}, err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return outboxState{}, nil
+		}
+		return outboxState{}, err
+	}
+
+	var s outboxState
+	if err := json.Unmarshal(b, &s); err != nil {
+		return outboxState{}, err
+	}
+	return s, nil
+}
+
+func saveOutbox(s outboxState) error {
+	path, err := outboxFilePath()
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// outboxBackoff 返回第 attempts 次失败后的等待时间：1m、2m、4m……最长 2h。
+func outboxBackoff(attempts int) time.Duration {
+	d := outboxBaseDelay
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= outboxMaxDelay {
+			return outboxMaxDelay
+		}
+	}
+	return d
+}
+
+func (m *Monitor) persistOutbox() {
+	m.persistMu.Lock()
+	defer m.persistMu.Unlock()
+	m.mu.Lock()
+	s := outboxState{
+		Pending: append([]outboxEntry(nil), m.outbox.Pending...),
+		Dead:    append([]outboxEntry(nil), m.outbox.Dead...),
+	}
+	m.mu.Unlock()
+	_ = saveOutbox(s)
+}
+
+// enqueueOutbox 记录一次失败的推送，稍后按指数退避重试。
+// 渠道只有部分接收方失败时，只重试失败的接收方，并遵守渠道要求的最短等待时间。
+func (m *Monitor) enqueueOutbox(notifier string, msg PushMessage, sendErr error) {
+	now := time.Now()
+	recipients, wait := retryHint(sendErr)
+	if len(recipients) > 0 {
+		msg.Recipients = recipients
+	}
+	m.mu.Lock()
+	m.outboxSeq++
+	m.outbox.Pending = append(m.outbox.Pending, outboxEntry{
+		ID:          strconv.FormatInt(now.UnixNano(), 36) + "-" + strconv.Itoa(m.outboxSeq),
+		Notifier:    notifier,
+		Message:     msg,
+		Attempts:    1,
+		NextAttempt: now.Add(max(outboxBackoff(1), wait)),
+		LastError:   sendErr.Error(),
+		CreatedAt:   now,
+	})
+	m.mu.Unlock()
+	m.persistOutbox()
+}
+
+// moveToDeadLocked 把条目转入死信，调用方需持有 m.mu。
+func (m *Monitor) moveToDeadLocked(e outboxEntry) {
+	m.outbox.Dead = append(m.outbox.Dead, e)
+	if len(m.outbox.Dead) > outboxMaxDead {
+		m.outbox.Dead = m.outbox.Dead[len(m.outbox.Dead)-outboxMaxDead:]
+	}
+}
+
+// runOutboxRetries 在监控运行期间定时重试到期的失败推送，与来源检查互不等待。
+func (m *Monitor) runOutboxRetries(ctx context.Context, appCtx context.Context) {
+	t := time.NewTicker(outboxRetryTick)
+	defer t.Stop()
+	for {
+		m.retryOutbox(ctx, appCtx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// retryOutbox 重试所有到期的失败推送。渠道已被删除或停用的条目直接转入死信。
+func (m *Monitor) retryOutbox(ctx context.Context, appCtx context.Context) {
+	now := time.Now()
+
+	m.mu.Lock()
+	byName := map[string]Notifier{}
+	for _, n := range m.notifiers {
+		byName[n.Name()] = n
+	}
+	var due []outboxEntry
+	var keep []outboxEntry
+	for _, e := range m.outbox.Pending {
+		if e.NextAttempt.After(now) {
+			keep = append(keep, e)
+		} else {
+			due = append(due, e)
+		}
+	}
+	m.outbox.Pending = keep
+	m.mu.Unlock()
+
+	if len(due) == 0 {
+		return
+	}
+	m.emitLog(appCtx, "INFO", "开始重试失败推送: "+strconv.Itoa(len(due))+" 条")
+
+	for _, e := range due {
+		if ctx.Err() != nil {
+			// 停止监控时把未处理的条目原样放回。
+			m.mu.Lock()
+			m.outbox.Pending = append(m.outbox.Pending, e)
+			m.mu.Unlock()
+			continue
+		}
+
+		n, ok := byName[e.Notifier]
+		if !ok {
+			e.LastError = "推送渠道已删除或停用"
+			m.mu.Lock()
+			m.moveToDeadLocked(e)
+			m.mu.Unlock()
+			m.emitLog(appCtx, "ERROR", e.Notifier+"推送转入死信: "+e.Message.Title+"（"+e.LastError+"）")
+			continue
+		}
+
+		err := n.Send(ctx, e.Message)
+		e.Attempts++
+		if err == nil {
+			m.emitLog(appCtx, "INFO", n.Name()+"重试推送成功: "+e.Message.Title)
+			continue
+		}
+
+		e.LastError = err.Error()
+		recipients, wait := retryHint(err)
+		if len(recipients) > 0 {
+			e.Message.Recipients = recipients
+		}
+		m.mu.Lock()
+		if e.Attempts >= outboxMaxAttempts {
+			m.moveToDeadLocked(e)
+		} else {
+			e.NextAttempt = time.Now().Add(max(outboxBackoff(e.Attempts), wait))
+			m.outbox.Pending = append(m.outbox.Pending, e)
+		}
+		m.mu.Unlock()
+
+		if e.Attempts >= outboxMaxAttempts {
+			m.emitLog(appCtx, "ERROR", n.Name()+"重试推送失败，已达上限转入死信: "+e.Message.Title+"（"+e.LastError+"）")
+		} else {
+			m.emitLog(appCtx, "WARN", n.Name()+"重试推送失败（第 "+strconv.Itoa(e.Attempts)+" 次）: "+err.Error())
+		}
+	}
+
+	m.persistOutbox()
+}
+
+// RequeueDeadLetters 把所有死信重新放回待重试队列，返回条数。
+func (m *Monitor) RequeueDeadLetters() int {
+	now := time.Now()
+	m.mu.Lock()
+	n := len(m.outbox.Dead)
+	for _, e := range m.outbox.Dead {
+		e.Attempts = 0
+		e.NextAttempt = now
+		m.outbox.Pending = append(m.outbox.Pending, e)
+	}
+	m.outbox.Dead = nil
+	m.mu.Unlock()
+	m.persistOutbox()
+	return n
+}
+
+// ClearDeadLetters 丢弃所有死信。
+func (m *Monitor) ClearDeadLetters() {
+	m.mu.Lock()
+	m.outbox.Dead = nil
+	m.mu.Unlock()
+	m.persistOutbox()
+}
+
+// lastDeadLetterLocked 返回最近一条死信的描述，调用方需持有 m.mu。
+func (m *Monitor) lastDeadLetterLocked() string {
+	if len(m.outbox.Dead) == 0 {
+		return ""
+	}
+	e := m.outbox.Dead[len(m.outbox.Dead)-1]
+	return strings.TrimSpace(e.Notifier + "：" + e.Message.Title + "（" + e.LastError + "）")
+}
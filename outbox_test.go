@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{7, 64 * time.Minute},
+		{8, outboxMaxDelay},
+		{30, outboxMaxDelay},
+	}
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %v, 期望 %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestPushAllQueuesFailedNotifier(t *testing.T) {
+	useTempConfigDir(t)
+
+	m := NewMonitor()
+	failing := &recordNotifier{name: "乙", err: errors.New("HTTP 500")}
+	before := time.Now()
+	m.pushAll(context.Background(), nil, []Notifier{&recordNotifier{name: "甲"}, failing}, testPushMessage())
+
+	if len(m.outbox.Pending) != 1 {
+		t.Fatalf("Pending = %+v", m.outbox.Pending)
+	}
+	e := m.outbox.Pending[0]
+	if e.Notifier != "乙" || e.Attempts != 1 || e.LastError != "HTTP 500" || e.NextAttempt.Before(before.Add(time.Minute)) {
+		t.Errorf("entry = %+v", e)
+	}
+	saved, err := loadOutbox()
+	if err != nil || len(saved.Pending) != 1 || saved.Pending[0].ID != e.ID {
+		t.Errorf("发件箱未保存: %+v, %v", saved, err)
+	}
+}
+
+func TestRetryOutbox(t *testing.T) {
+	useTempConfigDir(t)
+
+	m := NewMonitor()
+	ok := &recordNotifier{name: "甲"}
+	failing := &recordNotifier{name: "乙", err: errors.New("HTTP 502")}
+	m.notifiers = []Notifier{ok, failing}
+
+	now := time.Now()
+	due := now.Add(-time.Second)
+	entry := func(id string, notifier string, attempts int, next time.Time) outboxEntry {
+		msg := testPushMessage()
+		msg.Title = id
+		return outboxEntry{ID: id, Notifier: notifier, Message: msg, Attempts: attempts, NextAttempt: next}
+	}
+	m.outbox.Pending = []outboxEntry{
+		entry("重试成功", "甲", 1, due),
+		entry("再次失败", "乙", 1, due),
+		entry("达到上限", "乙", outboxMaxAttempts-1, due),
+		entry("未到期", "甲", 1, now.Add(time.Hour)),
+		entry("渠道已删除", "丙", 1, due),
+	}
+
+	m.retryOutbox(context.Background(), nil)
+
+	if ok.count() != 1 || failing.count() != 2 {
+		t.Errorf("发送次数 甲=%d 乙=%d", ok.count(), failing.count())
+	}
+	pending := map[string]outboxEntry{}
+	for _, e := range m.outbox.Pending {
+		pending[e.ID] = e
+	}
+	if len(pending) != 2 {
+		t.Fatalf("Pending = %+v", m.outbox.Pending)
+	}
+	if e := pending["再次失败"]; e.Attempts != 2 || e.LastError != "HTTP 502" || e.NextAttempt.Before(now.Add(2*time.Minute)) {
+		t.Errorf("再次失败 = %+v", e)
+	}
+	if e := pending["未到期"]; e.Attempts != 1 {
+		t.Errorf("未到期 = %+v", e)
+	}
+
+	if len(m.outbox.Dead) != 2 || m.outbox.Dead[0].ID != "达到上限" || m.outbox.Dead[1].ID != "渠道已删除" {
+		t.Fatalf("Dead = %+v", m.outbox.Dead)
+	}
+	if got := m.lastDeadLetterLocked(); got != "丙：渠道已删除（推送渠道已删除或停用）" {
+		t.Errorf("lastDeadLetter = %q", got)
+	}
+	saved, err := loadOutbox()
+	if err != nil || len(saved.Pending) != 2 || len(saved.Dead) != 2 {
+		t.Errorf("发件箱未保存: %+v, %v", saved, err)
+	}
+
+	if n := m.RequeueDeadLetters(); n != 2 {
+		t.Errorf("RequeueDeadLetters = %d", n)
+	}
+	if len(m.outbox.Dead) != 0 || len(m.outbox.Pending) != 4 {
+		t.Fatalf("重新入队后 %+v", m.outbox)
+	}
+	for _, e := range m.outbox.Pending[2:] {
+		if e.Attempts != 0 || e.NextAttempt.After(time.Now()) {
+			t.Errorf("重新入队的条目 = %+v", e)
+		}
+	}
+}
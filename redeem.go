@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// maxRedeemCodes 为保留的已见兑换码数量上限。
+const maxRedeemCodes = 500
+
+var (
+	// redeemCodeRe 匹配紧跟在关键词与分隔符（冒号、“为”或空白）之后的兑换码，可带引号或括号。
+	redeemCodeRe = regexp.MustCompile(`(?i)(?:兑换码|礼包码|激活码|cdkey|cdk)(?:\s*[:：]\s*|\s*为\s*|\s+)[「“"【\[]?([A-Za-z0-9]{6,32})\b`)
+	// redeemListRe 匹配同一处列出的后续兑换码，如“兑换码：AAA111、BBB222”。
+	redeemListRe = regexp.MustCompile(`^[」”"】\]]?\s*(?:[、，,/]|或)\s*[「“"【\[]?([A-Za-z0-9]{6,32})\b`)
+	// redeemNoiseRe 为关键词前出现时说明后面是联系方式或公众号回复词，而不是兑换码。
+	redeemNoiseRe = regexp.MustCompile(`微信|公众号|群|客服`)
+)
+
+// redeemCodeSource 为从公告或活动页面中提取到的新兑换码，标题中带兑换码，并以高优先级推送。
+type redeemCodeSource struct{}
+
+func (redeemCodeSource) Name() string     { return "兑换码" }
+func (redeemCodeSource) PushHead() string { return "新兑换码：{{.Title}}" }
+func (redeemCodeSource) Priority() string { return pushPriorityHigh }
+
+// isRedeemCodeLike 判断字符串是否像兑换码：同时含字母和数字，或为至少 8 位的大写字母。
+// 纯数字多为日期、QQ 群号等，不算兑换码。
+func isRedeemCodeLike(s string) bool {
+	var letters, digits, upper int
+	for _, r := range s {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case unicode.IsUpper(r):
+			letters++
+			upper++
+		default:
+			letters++
+		}
+	}
+	if letters > 0 && digits > 0 {
+		return true
+	}
+	return digits == 0 && upper == len(s) && len(s) >= 8
+}
+
+// extractRedeemCodes 查找紧跟在“兑换码”“礼包码”等关键词与分隔符之后的兑换码，按出现顺序去重返回。
+// 以 QQ 开头的号码，以及同一分句中关键词前提到微信、公众号、群或客服的，都不算兑换码。
+func extractRedeemCodes(text string) []string {
+	var out []string
+	seen := map[string]bool{}
+	add := func(code string) {
+		if strings.HasPrefix(strings.ToLower(code), "qq") || !isRedeemCodeLike(code) || seen[code] {
+			return
+		}
+		seen[code] = true
+		out = append(out, code)
+	}
+	for _, loc := range redeemCodeRe.FindAllStringSubmatchIndex(text, -1) {
+		if redeemNoiseRe.MatchString(clauseBefore(text, loc[0])) {
+			continue
+		}
+		add(text[loc[2]:loc[3]])
+		rest := text[loc[1]:]
+		for {
+			next := redeemListRe.FindStringSubmatchIndex(rest)
+			if next == nil {
+				break
+			}
+			add(rest[next[2]:next[3]])
+			rest = rest[next[1]:]
+		}
+	}
+	return out
+}
+
+// clauseBefore 返回 text[:end] 末尾同一分句内的文字，最多 20 个字。
+func clauseBefore(text string, end int) string {
+	before := []rune(text[:end])
+	if len(before) > 20 {
+		before = before[len(before)-20:]
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		if strings.ContainsRune("。！？!?；;\n", before[i]) {
+			return string(before[i+1:])
+		}
+	}
+	return string(before)
+}
+
+// checkRedeemCodes 从条目的标题与正文中提取兑换码，与已见兑换码比较后逐个推送新兑换码。
+func (m *Monitor) checkRedeemCodes(ctx context.Context, appCtx context.Context, run checkRun, c checker, item latestItem) {
+	codes := extractRedeemCodes(item.Title + "\n" + item.Body)
+	if len(codes) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	known := map[string]bool{}
+	for _, code := range m.redeemCodes {
+		known[code] = true
+	}
+	var fresh []string
+	for _, code := range codes {
+		if !known[code] {
+			fresh = append(fresh, code)
+			m.redeemCodes = append(m.redeemCodes, code)
+		}
+	}
+	if len(m.redeemCodes) > maxRedeemCodes {
+		m.redeemCodes = m.redeemCodes[len(m.redeemCodes)-maxRedeemCodes:]
+	}
+	m.mu.Unlock()
+
+	if len(fresh) == 0 {
+		return
+	}
+	m.persistSnapshot()
+
+	for _, code := range fresh {
+		m.emitLog(appCtx, "INFO", "检测到新兑换码: "+code)
+		// 条目本身已按规则打开或推送过，兑换码只推送，不再打开链接。
+		m.pushItem(ctx, appCtx, run, run.notifiers, redeemCodeSource{}, latestItem{
+			Key:     code,
+			Title:   code,
+			Link:    item.Link,
+			Summary: "来自" + c.Name() + "：" + item.Title,
+		}, nil)
+	}
+}
+
+// attachPageText 读取条目链接的整页文字作为正文，用于在活动页中查找兑换码；失败时只记录日志。
+func (m *Monitor) attachPageText(ctx context.Context, appCtx context.Context, item *latestItem) {
+	if item.Body != "" || strings.TrimSpace(item.Link) == "" {
+		return
+	}
+	paragraphs, err := fetchArticleParagraphs(ctx, m.httpClient, item.Link, "body")
+	if err != nil {
+		m.emitLog(appCtx, "WARN", "读取页面内容失败: "+err.Error())
+		return
+	}
+	item.Body = strings.Join(paragraphs, "\n")
+}
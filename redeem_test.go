@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestExtractRedeemCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"冒号", "本周福利兑换码：TLHJ2026GIFT，有效期至10月31日。", []string{"TLHJ2026GIFT"}},
+		{"为", "周年庆礼包码为 HJ8K2M9Q，可在游戏内兑换。", []string{"HJ8K2M9Q"}},
+		{"英文关键词与半角冒号", "CDK: abc123xyz (each account once)", []string{"abc123xyz"}},
+		{"空白", "兑换码 NEWYEARGIFT 限时领取", []string{"NEWYEARGIFT"}},
+		{"括号", "激活码：「TLBB2026A」", []string{"TLBB2026A"}},
+		{"并列多个", "兑换码：A1B2C3D4、E5F6G7H8 或 J9K8L7M6", []string{"A1B2C3D4", "E5F6G7H8", "J9K8L7M6"}},
+		{"去重", "兑换码：TLHJ1016。再次提醒，兑换码：TLHJ1016", []string{"TLHJ1016"}},
+
+		{"客服 QQ", "兑换码领取问题请联系客服QQ800012345", nil},
+		{"兑换码后直接是 QQ 号", "兑换码：QQ800012345", nil},
+		{"关键词在前的公众号", "兑换码请关注官方微信公众号tlhj2021获取", nil},
+		{"关键词在后的公众号", "关注官方微信公众号tlhj2021获取兑换码", nil},
+		{"公众号回复词", "关注公众号回复礼包码 tlhj2021 即可领取", nil},
+		{"加群", "加入官方玩家群领取礼包码 ABC12345", nil},
+		{"尚未公布", "兑换码将于10月20日公布，敬请期待", nil},
+		{"纯数字", "兑换码：20261016", nil},
+		{"链接", "兑换码详见 https://tlhj.changyou.com/gift?code=ABC123", nil},
+	}
+	for _, tt := range tests {
+		if got := extractRedeemCodes(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: extractRedeemCodes(%q) = %v, 期望 %v", tt.name, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCheckActivitiesScansEveryNewActivity(t *testing.T) {
+	useTempConfigDir(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a1", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><p>活动一</p><p>兑换码：AAAA1111</p></body></html>`))
+	})
+	mux.HandleFunc("/a2", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><p>活动二</p><p>礼包码为 BBBB2222</p></body></html>`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	m := NewMonitor()
+	m.lastActKey = srv.URL + "/a0"
+	m.actSeenKeys = []string{srv.URL + "/a0"}
+	m.mu.Lock()
+	run := m.newCheckRunLocked(time.Now())
+	m.mu.Unlock()
+
+	all := []latestItem{
+		{Key: srv.URL + "/a2", Title: "活动二", Link: srv.URL + "/a2"},
+		{Key: srv.URL + "/a1", Title: "活动一", Link: srv.URL + "/a1"},
+		{Key: srv.URL + "/a0", Title: "活动零", Link: srv.URL + "/a0"},
+	}
+	m.checkActivities(context.Background(), nil, run, activityChecker{}, all)
+
+	m.mu.Lock()
+	codes := append([]string(nil), m.redeemCodes...)
+	m.mu.Unlock()
+	if want := []string{"AAAA1111", "BBBB2222"}; !slices.Equal(codes, want) {
+		t.Fatalf("兑换码 = %v, 期望 %v", codes, want)
+	}
+	// 兑换码只推送，不作为新条目再次打开链接或记入历史。
+	var titles []string
+	for _, e := range m.History() {
+		titles = append(titles, e.Title)
+	}
+	if want := []string{"活动一"}; !slices.Equal(titles, want) {
+		t.Fatalf("历史 = %v, 期望 %v", titles, want)
+	}
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 规则动作类型。
+const (
+	ruleActionOpen     = "open"     // 打开链接
+	ruleActionPush     = "push"     // 推送到指定渠道（为空表示全部渠道）
+	ruleActionSound    = "sound"    // 向前端发送声音事件
+	ruleActionCommand  = "command"  // 运行本地命令
+	ruleActionSuppress = "suppress" // 停止执行后续动作（包括后续规则）
+
+	ruleCommandTimeout = time.Minute
+)
+
+// Rule 把条件映射到一组有序动作。多条规则按顺序匹配，命中规则的动作依次执行；
+// 没有规则命中时按过滤规则处理。
+type Rule struct {
+	Name    string        `json:"name"`
+	Enabled bool          `json:"enabled"`
+	When    RuleCondition `json:"when"`
+	Actions []RuleAction  `json:"actions"`
+}
+
+// RuleCondition 中各项均为空时视为不限制，多项之间为“且”。
+type RuleCondition struct {
+	Sources []string `json:"sources"`
+	// Title 与过滤规则语法一致，任一条命中即可。
+	Title []string `json:"title"`
+	// TimeFrom/TimeTo 为北京时间 "HH:MM"，TimeTo 早于 TimeFrom 时表示跨过午夜。
+	TimeFrom string `json:"timeFrom"`
+	TimeTo   string `json:"timeTo"`
+	// Weekdays 取值 0-6，0 为周日，同样按北京时间计算，与检查时段一致。
+	Weekdays []int `json:"weekdays"`
+}
+
+type RuleAction struct {
+	Type      string   `json:"type"`
+	Notifiers []string `json:"notifiers,omitempty"`
+	// Sound 为音频地址（http(s)、data: 或以 / 开头的路径），其他取值播放内置提示音。
+	Sound   string   `json:"sound,omitempty"`
+	Command string   `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// RuleSample 为试运行规则时使用的示例条目，Time 为 RFC3339 格式，留空表示当前时间。
+type RuleSample struct {
+	Source string `json:"source"`
+	Title  string `json:"title"`
+	Time   string `json:"time"`
+}
+
+// RuleDryRun 为规则试运行结果：命中的规则与最终会执行的动作。
+type RuleDryRun struct {
+	MatchedRules []string     `json:"matchedRules"`
+	UsedFilter   bool         `json:"usedFilter"`
+	Keywords     []string     `json:"keywords"`
+	Actions      []RuleAction `json:"actions"`
+	Summary      []string     `json:"summary"`
+}
+
+// itemPlan 为对一个新条目将要执行的动作。
+type itemPlan struct {
+	matchedRules []string
+	usedFilter   bool
+	keywords     []string
+	actions      []RuleAction
+}
+
+// parseClock 解析 "HH:MM"，返回自零点起的分钟数。
+func parseClock(s string) (int, error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return 0, errors.New("时间格式应为 HH:MM: " + s)
+	}
+	hour, err1 := strconv.Atoi(h)
+	minute, err2 := strconv.Atoi(m)
+	if err1 != nil || err2 != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, errors.New("时间格式应为 HH:MM: " + s)
+	}
+	return hour*60 + minute, nil
+}
+
+func validateRules(rules []Rule) error {
+	for i, r := range rules {
+		name := strings.TrimSpace(r.Name)
+		if name == "" {
+			name = "第 " + strconv.Itoa(i+1) + " 条规则"
+		}
+		if _, err := compilePatterns(r.When.Title); err != nil {
+			return errors.New(name + ": " + err.Error())
+		}
+		from, to := strings.TrimSpace(r.When.TimeFrom), strings.TrimSpace(r.When.TimeTo)
+		if (from == "") != (to == "") {
+			return errors.New(name + ": 时间段需同时填写开始与结束")
+		}
+		if from != "" {
+			if _, err := parseClock(from); err != nil {
+				return errors.New(name + ": " + err.Error())
+			}
+			if _, err := parseClock(to); err != nil {
+				return errors.New(name + ": " + err.Error())
+			}
+		}
+		for _, d := range r.When.Weekdays {
+			if d < 0 || d > 6 {
+				return errors.New(name + ": 星期取值应为 0-6")
+			}
+		}
+		if len(r.Actions) == 0 {
+			return errors.New(name + ": 至少需要一个动作")
+		}
+		for _, a := range r.Actions {
+			switch strings.ToLower(strings.TrimSpace(a.Type)) {
+			case ruleActionOpen, ruleActionPush, ruleActionSuppress:
+			case ruleActionSound:
+				if strings.TrimSpace(a.Sound) == "" {
+					return errors.New(name + ": 声音动作需填写音频地址或提示音名称")
+				}
+			case ruleActionCommand:
+				if strings.TrimSpace(a.Command) == "" {
+					return errors.New(name + ": 命令动作需填写命令")
+				}
+			default:
+				return errors.New(name + ": 未知动作类型 " + a.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// matchRule 判断规则条件是否满足，返回标题命中的关键词。
+func matchRule(r Rule, source string, title string, at time.Time) (bool, []string) {
+	c := r.When
+	at = at.In(beijingTime)
+	if len(c.Sources) > 0 {
+		ok := false
+		for _, s := range c.Sources {
+			if strings.TrimSpace(s) == source {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	if len(c.Weekdays) > 0 {
+		ok := false
+		for _, d := range c.Weekdays {
+			if time.Weekday(d) == at.Weekday() {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	if strings.TrimSpace(c.TimeFrom) != "" {
+		from, err1 := parseClock(c.TimeFrom)
+		to, err2 := parseClock(c.TimeTo)
+		if err1 != nil || err2 != nil {
+			return false, nil
+		}
+		now := at.Hour()*60 + at.Minute()
+		in := now >= from && now < to
+		if to <= from {
+			in = now >= from || now < to
+		}
+		if !in {
+			return false, nil
+		}
+	}
+
+	patterns, _ := compilePatterns(c.Title)
+	if len(patterns) == 0 {
+		return true, nil
+	}
+	folded := foldWidth(title)
+	var matched []string
+	for _, p := range patterns {
+		if hit, ok := p.match(folded); ok {
+			matched = append(matched, hit)
+		}
+	}
+	return len(matched) > 0, matched
+}
+
+// planActions 依次匹配启用的规则并汇总动作，遇到 suppress 即停止；
+// 没有规则命中时由过滤规则决定打开链接和推送。
+func planActions(rules []Rule, filters []FilterRule, source string, title string, at time.Time) itemPlan {
+	var plan itemPlan
+	for _, r := range rules {
+		if !r.Enabled {
+			continue
+		}
+		ok, matched := matchRule(r, source, title, at)
+		if !ok {
+			continue
+		}
+		plan.matchedRules = append(plan.matchedRules, r.Name)
+		plan.keywords = append(plan.keywords, matched...)
+		for _, a := range r.Actions {
+			a.Type = strings.ToLower(strings.TrimSpace(a.Type))
+			plan.actions = append(plan.actions, a)
+			if a.Type == ruleActionSuppress {
+				return plan
+			}
+		}
+	}
+	if len(plan.matchedRules) > 0 {
+		return plan
+	}
+
+	decision := evaluateFilters(filters, source, title)
+	plan.usedFilter = true
+	plan.keywords = decision.Matched
+	if decision.Open {
+		plan.actions = append(plan.actions, RuleAction{Type: ruleActionOpen})
+	}
+	if decision.Push {
+		plan.actions = append(plan.actions, RuleAction{Type: ruleActionPush})
+	}
+	return plan
+}
+
+// describeAction 返回动作的中文描述，用于日志与试运行结果。
+func describeAction(a RuleAction) string {
+	switch a.Type {
+	case ruleActionOpen:
+		return "打开链接"
+	case ruleActionPush:
+		if len(a.Notifiers) == 0 {
+			return "推送到全部渠道"
+		}
+		return "推送到 " + strings.Join(a.Notifiers, "、")
+	case ruleActionSound:
+		return "播放声音 " + a.Sound
+	case ruleActionCommand:
+		return "运行命令 " + strings.TrimSpace(a.Command+" "+strings.Join(a.Args, " "))
+	case ruleActionSuppress:
+		return "停止后续动作"
+	}
+	return a.Type
+}
+
+func (p itemPlan) dryRun() RuleDryRun {
+	out := RuleDryRun{
+		MatchedRules: p.matchedRules,
+		UsedFilter:   p.usedFilter,
+		Keywords:     p.keywords,
+		Actions:      p.actions,
+	}
+	for _, a := range p.actions {
+		out.Summary = append(out.Summary, describeAction(a))
+	}
+	if len(out.Summary) == 0 {
+		out.Summary = []string{"仅记录日志"}
+	}
+	return out
+}
+
+// selectNotifiers 按名称挑选推送渠道，names 为空时返回全部渠道。
+func selectNotifiers(all []Notifier, names []string) []Notifier {
+	if len(names) == 0 {
+		return all
+	}
+	want := map[string]bool{}
+	for _, name := range names {
+		want[strings.TrimSpace(name)] = true
+	}
+	var out []Notifier
+	for _, n := range all {
+		if want[n.Name()] {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// runRuleCommand 运行规则中的本地命令，条目信息通过环境变量传入。
+func (m *Monitor) runRuleCommand(appCtx context.Context, a RuleAction, source string, item latestItem) {
+	ctx, cancel := context.WithTimeout(context.Background(), ruleCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, strings.TrimSpace(a.Command), a.Args...)
+	cmd.Env = append(os.Environ(),
+		"TLBB_SOURCE="+source,
+		"TLBB_TITLE="+item.Title,
+		"TLBB_LINK="+item.Link,
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := err.Error()
+		if s := strings.TrimSpace(string(out)); s != "" {
+			msg += ": " + s
+		}
+		m.emitLog(appCtx, "ERROR", "规则命令执行失败: "+msg)
+		return
+	}
+	m.emitLog(appCtx, "INFO", "规则命令执行完成: "+a.Command)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatchRuleUsesBeijingTime(t *testing.T) {
+	r := Rule{Enabled: true, When: RuleCondition{
+		TimeFrom: "20:00",
+		TimeTo:   "23:00",
+		Weekdays: []int{int(time.Friday)},
+	}}
+	// 北京时间周五 21:30，对应 UTC 周五 13:30、纽约周五 09:30。
+	at := time.Date(2026, 10, 16, 13, 30, 0, 0, time.UTC)
+	ny := time.FixedZone("EDT", -4*3600)
+	for _, tt := range []time.Time{at, at.In(ny), at.In(beijingTime)} {
+		if ok, _ := matchRule(r, "公告", "维护", tt); !ok {
+			t.Errorf("%v 应命中北京时间周五 20:00-23:00", tt)
+		}
+	}
+	// 北京时间周六 01:30，即 UTC 周五 17:30。
+	if ok, _ := matchRule(r, "公告", "维护", time.Date(2026, 10, 16, 17, 30, 0, 0, time.UTC)); ok {
+		t.Error("北京时间周六凌晨不应命中")
+	}
+}
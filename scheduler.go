@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// minScheduleIntervalSec 为允许配置的最短检查间隔，避免请求过于频繁。
+	minScheduleIntervalSec = 30
+	// schedulerMaxSleep 为调度器两次醒来的最长间隔，新增来源最多延迟这么久才开始检查。
+	schedulerMaxSleep = time.Minute
+	// maxConcurrentChecks 为同时检查的来源数上限。
+	maxConcurrentChecks = 4
+	// sourceCheckTimeout 为单个来源一次检查的默认总时长上限。
+	sourceCheckTimeout = 2 * time.Minute
+
+	// minBurstIntervalSec 与 maxBurstMinutes 限制加速检查的频率与时长。
+	minBurstIntervalSec = 10
+	maxBurstMinutes     = 120
+	// defaultBurstIntervalSec 与 defaultBurstMinutes 为托盘菜单“加速检查”使用的参数。
+	defaultBurstIntervalSec = 30
+	defaultBurstMinutes     = 15
+)
+
+// SourceSchedule 为某个来源的检查间隔：每次检查后等待 IntervalSec 秒，再加上 0 到 JitterSec 秒的随机抖动。
+// Source 留空表示所有未单独配置的来源；都未配置时为 5-10 分钟。
+type SourceSchedule struct {
+	Source      string `json:"source"`
+	IntervalSec int    `json:"intervalSec"`
+	JitterSec   int    `json:"jitterSec"`
+}
+
+func defaultSourceSchedule() SourceSchedule {
+	return SourceSchedule{IntervalSec: minIntervalSec, JitterSec: maxIntervalSec - minIntervalSec}
+}
+
+// ScheduleSlot 为按星期与时段调整的检查间隔（北京时间），如维护日上午加快、深夜放慢。
+// Weekdays 为 0（周日）到 6（周六），留空表示每天；时段为 [StartHour, EndHour)，StartHour 大于 EndHour 时跨过午夜。
+type ScheduleSlot struct {
+	Weekdays    []int `json:"weekdays"`
+	StartHour   int   `json:"startHour"`
+	EndHour     int   `json:"endHour"`
+	IntervalSec int   `json:"intervalSec"`
+	JitterSec   int   `json:"jitterSec"`
+}
+
+// matches 判断时刻是否落在该时段内；跨过午夜的时段按开始那天的星期计算。
+func (s ScheduleSlot) matches(t time.Time) bool {
+	t = t.In(beijingTime)
+	hour, day := t.Hour(), int(t.Weekday())
+	switch {
+	case s.StartHour < s.EndHour:
+		if hour < s.StartHour || hour >= s.EndHour {
+			return false
+		}
+	case s.StartHour > s.EndHour:
+		if hour < s.StartHour && hour >= s.EndHour {
+			return false
+		}
+		if hour < s.EndHour {
+			day = (day + 6) % 7
+		}
+	}
+	if len(s.Weekdays) == 0 {
+		return true
+	}
+	for _, d := range s.Weekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// findSchedule 返回来源在时刻 now 适用的检查间隔：优先来源名称完全一致的配置，
+// 其次为第一个匹配当前时段的 profile，再次为不限来源的配置。
+func findSchedule(schedules []SourceSchedule, profile []ScheduleSlot, source string, now time.Time) SourceSchedule {
+	generic, found := defaultSourceSchedule(), false
+	for _, s := range schedules {
+		name := strings.TrimSpace(s.Source)
+		if name == source {
+			return s
+		}
+		if name == "" && !found {
+			generic, found = s, true
+		}
+	}
+	for _, slot := range profile {
+		if slot.matches(now) {
+			return SourceSchedule{Source: source, IntervalSec: slot.IntervalSec, JitterSec: slot.JitterSec}
+		}
+	}
+	return generic
+}
+
+func validateSchedules(schedules []SourceSchedule) error {
+	used := map[string]bool{}
+	for _, s := range schedules {
+		name := strings.TrimSpace(s.Source)
+		label := name
+		if label == "" {
+			label = "全部来源"
+		}
+		if used[name] {
+			return errors.New(label + ": 检查间隔重复配置")
+		}
+		used[name] = true
+		if s.IntervalSec < minScheduleIntervalSec {
+			return errors.New(label + ": 检查间隔不能少于 30 秒")
+		}
+		if s.JitterSec < 0 {
+			return errors.New(label + ": 随机抖动不能为负数")
+		}
+	}
+	return nil
+}
+
+func validateScheduleProfile(profile []ScheduleSlot) error {
+	for i, slot := range profile {
+		label := "时段 " + strconv.Itoa(i+1)
+		for _, d := range slot.Weekdays {
+			if d < 0 || d > 6 {
+				return errors.New(label + ": 星期应为 0-6")
+			}
+		}
+		if slot.StartHour < 0 || slot.StartHour > 23 || slot.EndHour < 0 || slot.EndHour > 24 {
+			return errors.New(label + ": 开始时间应为 0-23 点，结束时间应为 0-24 点")
+		}
+		if slot.StartHour == slot.EndHour {
+			return errors.New(label + ": 开始与结束时间不能相同")
+		}
+		if slot.IntervalSec < minScheduleIntervalSec {
+			return errors.New(label + ": 检查间隔不能少于 30 秒")
+		}
+		if slot.JitterSec < 0 {
+			return errors.New(label + ": 随机抖动不能为负数")
+		}
+	}
+	return nil
+}
+
+// nextIntervalLocked 计算来源下次检查前的等待时间：加速检查期间固定为加速间隔，
+// 否则按来源与当前时段的配置计算；等待期间进入间隔更短的时段时，按新时段的间隔提前检查。
+// 调用方需持有 m.mu。
+func (m *Monitor) nextIntervalLocked(source string) time.Duration {
+	now := time.Now()
+	if now.Before(m.burstUntil) {
+		return m.burstInterval
+	}
+	s := findSchedule(m.schedules, m.schedProfile, source, now)
+	d := time.Duration(s.IntervalSec) * time.Second
+	if s.JitterSec > 0 {
+		d += time.Duration(m.rng.Intn(s.JitterSec+1)) * time.Second
+	}
+	if len(m.schedProfile) == 0 {
+		return d
+	}
+	for at := now.Truncate(time.Hour).Add(time.Hour); at.Before(now.Add(d)); at = at.Add(time.Hour) {
+		if next := findSchedule(m.schedules, m.schedProfile, source, at); next.IntervalSec < s.IntervalSec {
+			d = min(d, at.Sub(now)+time.Duration(next.IntervalSec)*time.Second)
+		}
+	}
+	return d
+}
+
+// StartBurst 开启加速检查：接下来 minutes 分钟内每 intervalSec 秒检查一次所有来源，到期后恢复原有间隔。
+// 调度器会被立即唤醒并马上检查一轮。
+func (m *Monitor) StartBurst(intervalSec int, minutes int) error {
+	if intervalSec < minBurstIntervalSec {
+		return errors.New("加速检查间隔不能少于 " + strconv.Itoa(minBurstIntervalSec) + " 秒")
+	}
+	if minutes <= 0 || minutes > maxBurstMinutes {
+		return errors.New("加速检查时长应为 1-" + strconv.Itoa(maxBurstMinutes) + " 分钟")
+	}
+
+	m.mu.Lock()
+	if !m.running {
+		m.mu.Unlock()
+		return errors.New("监控未运行")
+	}
+	m.burstInterval = time.Duration(intervalSec) * time.Second
+	m.burstUntil = time.Now().Add(time.Duration(minutes) * time.Minute)
+	appCtx := m.appCtx
+	m.mu.Unlock()
+
+	select {
+	case m.burstWake <- struct{}{}:
+	default:
+	}
+	m.emitLog(appCtx, "INFO", "已开启加速检查：每 "+strconv.Itoa(intervalSec)+" 秒检查一次，持续 "+strconv.Itoa(minutes)+" 分钟")
+	return nil
+}
+
+// StopBurst 提前结束加速检查，各来源在下次检查后恢复原有间隔。
+func (m *Monitor) StopBurst() {
+	m.mu.Lock()
+	active := time.Now().Before(m.burstUntil)
+	m.burstUntil = time.Time{}
+	appCtx := m.appCtx
+	m.mu.Unlock()
+
+	if active {
+		m.emitLog(appCtx, "INFO", "已结束加速检查")
+	}
+}
+
+// scheduledCheckers 返回当前要检查的全部来源。
+func (m *Monitor) scheduledCheckers() []checker {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	checks := []checker{announcementChecker{}, activityChecker{}}
+	checks = append(checks, m.forumCheckersLocked()...)
+	return append(checks, m.customCheckersLocked()...)
+}
+
+// runScheduler 按各来源自己的间隔独立检查 sources 返回的来源，直到 ctx 取消；返回前等待正在进行的检查结束。
+// 到期的来源交给最多 maxConcurrentChecks 个并发检查，同一来源不会同时检查两次，一个来源变慢不会拖住其他来源。
+// 设置中新增或删除的来源在下次醒来时生效；失败推送的重试在 runOutboxRetries 中单独进行。
+// 开启加速检查时立即醒来，并把所有来源视为到期。
+func (m *Monitor) runScheduler(ctx context.Context, appCtx context.Context, sources func() []checker) {
+	next := map[string]time.Time{}
+	checking := map[string]bool{}
+	// 检查结束后仍占着 sem 时写入 done，同时写入的不超过 maxConcurrentChecks 个，因此不会阻塞。
+	done := make(chan string, maxConcurrentChecks)
+	sem := make(chan struct{}, maxConcurrentChecks)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		checks := sources()
+
+		wake := time.Now().Add(schedulerMaxSleep)
+		active := map[string]bool{}
+		for _, c := range checks {
+			name := c.Name()
+			active[name] = true
+			if checking[name] {
+				continue
+			}
+			if at, ok := next[name]; ok && time.Now().Before(at) {
+				if at.Before(wake) {
+					wake = at
+				}
+				continue
+			}
+
+			checking[name] = true
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				m.runCheck(ctx, appCtx, c)
+				done <- name
+				<-sem
+			}()
+		}
+		for name := range next {
+			if !active[name] {
+				delete(next, name)
+			}
+		}
+
+		t := time.NewTimer(time.Until(wake))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		case <-m.burstWake:
+			t.Stop()
+			clear(next)
+		case name := <-done:
+			t.Stop()
+			delete(checking, name)
+			m.mu.Lock()
+			d := m.nextIntervalLocked(name)
+			m.mu.Unlock()
+			next[name] = time.Now().Add(d)
+			m.emitLog(appCtx, "INFO", name+"下次检查将在 "+d.String()+" 后")
+		}
+	}
+}
+
+// runCheck 在单独的超时内检查一个来源；出错或 panic 只记录日志，不影响其他来源。
+func (m *Monitor) runCheck(ctx context.Context, appCtx context.Context, c checker) {
+	name := c.Name()
+	defer func() {
+		if r := recover(); r != nil {
+			m.emitLog(appCtx, "ERROR", name+"检查异常: "+fmt.Sprint(r))
+		}
+	}()
+
+	timeout := m.checkTimeout
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := m.checkSource(checkCtx, appCtx, c)
+	switch {
+	case err == nil || ctx.Err() != nil:
+	case errors.Is(checkCtx.Err(), context.DeadlineExceeded):
+		m.emitLog(appCtx, "ERROR", name+"检查超时（"+timeout.String()+"）: "+err.Error())
+	default:
+		m.emitLog(appCtx, "ERROR", name+"检查失败: "+err.Error())
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// useTempConfigDir 把设置、历史等状态文件写到临时目录。
+func useTempConfigDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+}
+
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待超时: %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func seenKeys(m *Monitor, name string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.sourceStates[name].SeenKeys...)
+}
+
+func testHTMLSource(name string, url string) htmlSourceChecker {
+	return htmlSourceChecker{cfg: HTMLSourceConfig{
+		Name:         name,
+		Enabled:      true,
+		URL:          url,
+		ItemSelector: "li",
+	}}
+}
+
+func TestSchedulerSlowSourceDoesNotBlockOthers(t *testing.T) {
+	useTempConfigDir(t)
+
+	var version atomic.Int32
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v := version.Load()
+		fmt.Fprintf(w, `<ul><li><a href="/a%d">第 %d 条</a></li><li><a href="/a0">第 0 条</a></li></ul>`, v, v)
+	}))
+	defer fast.Close()
+
+	var slowHits atomic.Int32
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slowHits.Add(1)
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	m := NewMonitor()
+	m.checkTimeout = 300 * time.Millisecond
+	m.schedules = []SourceSchedule{{IntervalSec: 1}}
+	m.running = true
+
+	sources := []checker{
+		testHTMLSource("慢来源", slow.URL),
+		testHTMLSource("来源一", fast.URL+"/one"),
+		testHTMLSource("来源二", fast.URL+"/two"),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		m.runScheduler(ctx, nil, func() []checker { return sources })
+		close(stopped)
+	}()
+
+	waitFor(t, 3*time.Second, "快来源记录基线", func() bool {
+		return len(seenKeys(m, "来源一")) > 0 && len(seenKeys(m, "来源二")) > 0
+	})
+
+	version.Store(1)
+	waitFor(t, 5*time.Second, "快来源发现新增", func() bool {
+		return slices.Contains(seenKeys(m, "来源一"), fast.URL+"/a1") &&
+			slices.Contains(seenKeys(m, "来源二"), fast.URL+"/a1")
+	})
+	waitFor(t, 5*time.Second, "慢来源超时后重新检查", func() bool { return slowHits.Load() >= 2 })
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(3 * time.Second):
+		t.Fatal("取消后调度器未退出")
+	}
+
+	if keys := seenKeys(m, "慢来源"); len(keys) != 0 {
+		t.Fatalf("慢来源不应记录已见状态: %v", keys)
+	}
+	var detected []string
+	for _, e := range m.History() {
+		detected = append(detected, e.Source+":"+e.Title)
+	}
+	slices.Sort(detected)
+	if want := []string{"来源一:第 1 条", "来源二:第 1 条"}; !slices.Equal(detected, want) {
+		t.Fatalf("历史 = %v, 期望 %v", detected, want)
+	}
+}
+
+func TestRunCheckHonorsTimeout(t *testing.T) {
+	useTempConfigDir(t)
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	m := NewMonitor()
+	m.checkTimeout = 200 * time.Millisecond
+
+	start := time.Now()
+	m.runCheck(context.Background(), nil, testHTMLSource("慢来源", slow.URL))
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("runCheck 用时 %v，未按超时返回", elapsed)
+	}
+}
+
+func TestScheduleSlotMatches(t *testing.T) {
+	// 2026-10-13 为周二。
+	bj := func(day, hour, min int) time.Time { return time.Date(2026, 10, day, hour, min, 0, 0, beijingTime) }
+	utc := func(day, hour int) time.Time { return time.Date(2026, 10, day, hour, 0, 0, 0, time.UTC) }
+
+	maintenance := ScheduleSlot{Weekdays: []int{2}, StartHour: 6, EndHour: 12}
+	fridayNight := ScheduleSlot{Weekdays: []int{5}, StartHour: 23, EndHour: 7}
+	everyNight := ScheduleSlot{StartHour: 22, EndHour: 6}
+	weekend := ScheduleSlot{Weekdays: []int{0, 6}, StartHour: 0, EndHour: 24}
+
+	tests := []struct {
+		name string
+		slot ScheduleSlot
+		t    time.Time
+		want bool
+	}{
+		{"维护日时段内", maintenance, bj(13, 8, 0), true},
+		{"结束时刻不含", maintenance, bj(13, 12, 0), false},
+		{"开始时刻包含", maintenance, bj(13, 6, 0), true},
+		{"其他星期", maintenance, bj(14, 8, 0), false},
+		{"UTC 周二 0 点为北京时间 8 点", maintenance, utc(13, 0), true},
+		{"UTC 周一 23 点为北京时间周二 7 点", maintenance, utc(12, 23), true},
+		{"UTC 周二 5 点为北京时间 13 点", maintenance, utc(13, 5), false},
+		{"跨午夜开始当晚", fridayNight, bj(16, 23, 30), true},
+		{"跨午夜次日凌晨按开始那天计算", fridayNight, bj(17, 3, 0), true},
+		{"周五凌晨属于周四晚上", fridayNight, bj(16, 3, 0), false},
+		{"跨午夜结束时刻不含", fridayNight, bj(17, 7, 0), false},
+		{"周六晚上不匹配", fridayNight, bj(17, 23, 30), false},
+		{"UTC 周五 20 点为北京时间周六 4 点", fridayNight, utc(16, 20), true},
+		{"每天跨午夜的白天", everyNight, bj(13, 12, 0), false},
+		{"每天跨午夜的凌晨", everyNight, bj(13, 2, 0), true},
+		{"结束为 24 点表示到当天结束", weekend, bj(18, 23, 59), true},
+		{"全天时段仍按星期过滤", weekend, bj(19, 0, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.slot.matches(tt.t); got != tt.want {
+			t.Errorf("%s: matches(%v) = %v, 期望 %v", tt.name, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestValidateScheduleProfile(t *testing.T) {
+	tests := []struct {
+		slot ScheduleSlot
+		want string
+	}{
+		{ScheduleSlot{Weekdays: []int{2}, StartHour: 6, EndHour: 12, IntervalSec: 60}, ""},
+		{ScheduleSlot{StartHour: 23, EndHour: 7, IntervalSec: 1800}, ""},
+		{ScheduleSlot{StartHour: 0, EndHour: 24, IntervalSec: 600}, ""},
+		{ScheduleSlot{Weekdays: []int{7}, StartHour: 6, EndHour: 12, IntervalSec: 60}, "时段 1: 星期应为 0-6"},
+		{ScheduleSlot{StartHour: 24, EndHour: 6, IntervalSec: 60}, "时段 1: 开始时间应为 0-23 点，结束时间应为 0-24 点"},
+		{ScheduleSlot{StartHour: 8, EndHour: 8, IntervalSec: 60}, "时段 1: 开始与结束时间不能相同"},
+		{ScheduleSlot{StartHour: 6, EndHour: 12, IntervalSec: 10}, "时段 1: 检查间隔不能少于 30 秒"},
+		{ScheduleSlot{StartHour: 6, EndHour: 12, IntervalSec: 60, JitterSec: -5}, "时段 1: 随机抖动不能为负数"},
+	}
+	for _, tt := range tests {
+		err := validateScheduleProfile([]ScheduleSlot{tt.slot})
+		if (tt.want == "" && err != nil) || (tt.want != "" && (err == nil || err.Error() != tt.want)) {
+			t.Errorf("%+v: err = %v, 期望 %q", tt.slot, err, tt.want)
+		}
+	}
+}
+
+func TestNextIntervalWakesForFasterSlot(t *testing.T) {
+	m := NewMonitor()
+	m.schedules = []SourceSchedule{{IntervalSec: 2 * 3600}}
+	// 下一个整点开始的一小时内加快检查。
+	now := time.Now().In(beijingTime)
+	next := (now.Hour() + 1) % 24
+	m.schedProfile = []ScheduleSlot{{StartHour: next, EndHour: next + 1, IntervalSec: 60}}
+
+	m.mu.Lock()
+	d := m.nextIntervalLocked("公告")
+	m.mu.Unlock()
+	untilSlot := now.Truncate(time.Hour).Add(time.Hour).Sub(now)
+	if d > untilSlot+time.Minute+time.Second {
+		t.Errorf("间隔 %v, 应在进入加快时段后一分钟内检查（距时段开始 %v）", d, untilSlot)
+	}
+}
+
+func TestFindSchedulePrecedence(t *testing.T) {
+	schedules := []SourceSchedule{
+		{Source: "论坛", IntervalSec: 60},
+		{IntervalSec: 600},
+	}
+	profile := []ScheduleSlot{
+		{Weekdays: []int{2}, StartHour: 6, EndHour: 12, IntervalSec: 30},
+		{StartHour: 0, EndHour: 12, IntervalSec: 900},
+	}
+	tuesday := time.Date(2026, 10, 13, 8, 0, 0, 0, beijingTime)
+	wednesday := tuesday.AddDate(0, 0, 1)
+	evening := tuesday.Add(12 * time.Hour)
+
+	tests := []struct {
+		source    string
+		schedules []SourceSchedule
+		now       time.Time
+		want      int
+	}{
+		{"论坛", schedules, tuesday, 60},
+		{"公告", schedules, tuesday, 30},
+		{"公告", schedules, wednesday, 900},
+		{"公告", schedules, evening, 600},
+		{"公告", nil, evening, minIntervalSec},
+	}
+	for _, tt := range tests {
+		if got := findSchedule(tt.schedules, profile, tt.source, tt.now); got.IntervalSec != tt.want {
+			t.Errorf("%s %v: 间隔 %d, 期望 %d", tt.source, tt.now, got.IntervalSec, tt.want)
+		}
+	}
+}
+
+func TestValidateSchedules(t *testing.T) {
+	tests := []struct {
+		schedules []SourceSchedule
+		want      string
+	}{
+		{[]SourceSchedule{{IntervalSec: 300, JitterSec: 300}, {Source: "论坛", IntervalSec: 30}}, ""},
+		{[]SourceSchedule{{Source: "论坛", IntervalSec: 60}, {Source: " 论坛 ", IntervalSec: 90}}, "论坛: 检查间隔重复配置"},
+		{[]SourceSchedule{{IntervalSec: 60}, {IntervalSec: 90}}, "全部来源: 检查间隔重复配置"},
+		{[]SourceSchedule{{Source: "公告", IntervalSec: 29}}, "公告: 检查间隔不能少于 30 秒"},
+		{[]SourceSchedule{{Source: "公告", IntervalSec: 60, JitterSec: -1}}, "公告: 随机抖动不能为负数"},
+	}
+	for _, tt := range tests {
+		err := validateSchedules(tt.schedules)
+		if (tt.want == "" && err != nil) || (tt.want != "" && (err == nil || err.Error() != tt.want)) {
+			t.Errorf("%+v: err = %v, 期望 %q", tt.schedules, err, tt.want)
+		}
+	}
+}
+
+func TestNextIntervalPerSource(t *testing.T) {
+	m := NewMonitor()
+	m.schedules = []SourceSchedule{
+		{Source: "论坛", IntervalSec: 60},
+		{IntervalSec: 120, JitterSec: 30},
+	}
+	tests := []struct {
+		source   string
+		min, max time.Duration
+	}{
+		{"论坛", time.Minute, time.Minute},
+		{"公告", 2 * time.Minute, 150 * time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 50; i++ {
+			m.mu.Lock()
+			d := m.nextIntervalLocked(tt.source)
+			m.mu.Unlock()
+			if d < tt.min || d > tt.max {
+				t.Fatalf("%s: 间隔 %v, 期望在 %v 到 %v 之间", tt.source, d, tt.min, tt.max)
+			}
+		}
+	}
+
+	m.schedules = nil
+	m.mu.Lock()
+	d := m.nextIntervalLocked("公告")
+	m.mu.Unlock()
+	if d < minIntervalSec*time.Second || d > maxIntervalSec*time.Second {
+		t.Errorf("未配置时间隔 %v, 期望为默认的 5-10 分钟", d)
+	}
+}
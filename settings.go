@@ -12,19 +12,41 @@ const settingsDirName = "tlbb-notice-wails"
 const settingsFileName = "settings.json"
 
 type persistedSettings struct {
-	ChannelKey string `json:"channelKey"`
-
-	LastAnnounceKey   string `json:"lastAnnounceKey"`
-	LastAnnounceTitle string `json:"lastAnnounceTitle"`
-
-	LastActivityKey   string `json:"lastActivityKey"`
-	LastActivityTitle string `json:"lastActivityTitle"`
-	LastActivityLink  string `json:"lastActivityLink"`
+	ChannelKey string           `json:"channelKey"`
+	Notifiers  []NotifierConfig `json:"notifiers,omitempty"`
+
+	PushTemplates   map[string]PushTemplate `json:"pushTemplates,omitempty"`
+	FilterRules     []FilterRule            `json:"filterRules,omitempty"`
+	Rules           []Rule                  `json:"rules,omitempty"`
+	HTMLSources     []HTMLSourceConfig      `json:"htmlSources,omitempty"`
+	JSONSources     []JSONSourceConfig      `json:"jsonSources,omitempty"`
+	RSSSources      []RSSSourceConfig       `json:"rssSources,omitempty"`
+	ForumBoards     []ForumBoardConfig      `json:"forumBoards,omitempty"`
+	ForumThreads    []ForumThreadConfig     `json:"forumThreads,omitempty"`
+	AnnounceDetail  AnnounceDetailConfig    `json:"announceDetail"`
+	Maintenance     MaintenanceConfig       `json:"maintenance"`
+	Schedules       []SourceSchedule        `json:"schedules,omitempty"`
+	ScheduleProfile []ScheduleSlot          `json:"scheduleProfile,omitempty"`
+
+	LastAnnounceKey   string   `json:"lastAnnounceKey"`
+	LastAnnounceTitle string   `json:"lastAnnounceTitle"`
+	AnnounceSeenKeys  []string `json:"announceSeenKeys,omitempty"`
+	// 跟踪修改的公告正文指纹，按公告 key 索引
+	AnnounceContents map[string]announceContent `json:"announceContents,omitempty"`
+	// 已推送过的兑换码
+	RedeemCodes []string `json:"redeemCodes,omitempty"`
+
+	LastActivityKey   string   `json:"lastActivityKey"`
+	LastActivityTitle string   `json:"lastActivityTitle"`
+	LastActivityLink  string   `json:"lastActivityLink"`
 	ActivitySeenKeys  []string `json:"activitySeenKeys,omitempty"`
 
-	LastForumKey   string `json:"lastForumKey"`
-	LastForumTitle string `json:"lastForumTitle"`
-	LastForumLink  string `json:"lastForumLink"`
+	// 旧版本只监控一个论坛版块时的状态，读取后迁移到 SourceStates，不再写入
+	LastForumTitle string `json:"lastForumTitle,omitempty"`
+	LastForumLink  string `json:"lastForumLink,omitempty"`
+
+	// 论坛版块与自定义来源的已见状态，按来源名称索引
+	SourceStates map[string]sourceState `json:"sourceStates,omitempty"`
 
 	UpdatedAt string `json:"updatedAt"`
 }
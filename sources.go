@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// sourceState 为按名称索引的来源的持久化已见状态。
+type sourceState struct {
+	SeenKeys  []string `json:"seenKeys,omitempty"`
+	LastTitle string   `json:"lastTitle,omitempty"`
+	LastLink  string   `json:"lastLink,omitempty"`
+	// Marker 为来源自定义的变化标记，如关注帖子的回复数与最后回复时间。
+	Marker string `json:"marker,omitempty"`
+}
+
+// SourceStatus 为自定义来源在状态栏中展示的最新条目。
+type SourceStatus struct {
+	Name      string `json:"name"`
+	LastTitle string `json:"lastTitle"`
+	LastLink  string `json:"lastLink"`
+}
+
+func copySourceStates(in map[string]sourceState) map[string]sourceState {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]sourceState, len(in))
+	for name, st := range in {
+		st.SeenKeys = append([]string(nil), st.SeenKeys...)
+		out[name] = st
+	}
+	return out
+}
+
+// customCheckersLocked 返回设置中启用的自定义来源，调用方需持有 m.mu。
+func (m *Monitor) customCheckersLocked() []checker {
+	var out []checker
+	for _, cfg := range m.htmlSources {
+		if cfg.Enabled {
+			out = append(out, htmlSourceChecker{cfg: cfg})
+		}
+	}
+	for _, cfg := range m.jsonSources {
+		if cfg.Enabled {
+			out = append(out, jsonSourceChecker{cfg: cfg})
+		}
+	}
+	for _, cfg := range m.rssSources {
+		if cfg.Enabled {
+			out = append(out, rssSourceChecker{cfg: cfg})
+		}
+	}
+	for _, cfg := range m.forumThreads {
+		if cfg.Enabled {
+			out = append(out, threadChecker{cfg: cfg})
+		}
+	}
+	return out
+}
+
+// sourceStatusesLocked 返回各论坛版块与自定义来源的最新条目，按名称排序，调用方需持有 m.mu。
+func (m *Monitor) sourceStatusesLocked() []SourceStatus {
+	var out []SourceStatus
+	for name, st := range m.sourceStates {
+		if st.LastTitle == "" {
+			continue
+		}
+		out = append(out, SourceStatus{Name: name, LastTitle: st.LastTitle, LastLink: st.LastLink})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (m *Monitor) setSourceState(name string, st sourceState) {
+	m.mu.Lock()
+	if m.sourceStates == nil {
+		m.sourceStates = map[string]sourceState{}
+	}
+	m.sourceStates[name] = st
+	m.mu.Unlock()
+	m.persistSnapshot()
+}
+
+// checkListSource 处理按名称保存状态的列表来源：按已见集合逐条发现新增，
+// 首次运行时只记录基线。列表视为最新在前，新增条目按从旧到新的顺序处理。
+func (m *Monitor) checkListSource(ctx context.Context, appCtx context.Context, run checkRun, c checker, all []latestItem) {
+	name := c.Name()
+
+	m.mu.Lock()
+	st := m.sourceStates[name]
+	st.SeenKeys = append([]string(nil), st.SeenKeys...)
+	m.mu.Unlock()
+
+	if len(st.SeenKeys) == 0 {
+		st.SeenKeys = itemKeys(all)
+		st.LastTitle = all[0].Title
+		st.LastLink = all[0].Link
+		m.setSourceState(name, st)
+		m.emitLog(appCtx, "INFO", "已获取当前最新"+name+"(基线): "+all[0].Title)
+		return
+	}
+
+	newItems, updated := diffSeen(all, st.SeenKeys)
+	if len(newItems) == 0 {
+		m.emitLog(appCtx, "INFO", name+"未发现新增: "+all[0].Title)
+		return
+	}
+
+	st.SeenKeys = updated
+	st.LastTitle = newItems[0].Title
+	st.LastLink = newItems[0].Link
+	m.setSourceState(name, st)
+
+	for i := len(newItems) - 1; i >= 0; i-- {
+		m.emitLog(appCtx, "INFO", "检测到"+name+"新增: "+newItems[i].Title)
+		m.deliver(ctx, appCtx, run, c, newItems[i])
+	}
+}
+
+// customSourceNames 返回论坛版块与所有自定义来源的名称，用于保存设置时校验重名。
+func customSourceNames(s AppSettings) []string {
+	names := forumBoardNames(s.ForumBoards)
+	for _, cfg := range s.HTMLSources {
+		names = append(names, strings.TrimSpace(cfg.Name))
+	}
+	for _, cfg := range s.JSONSources {
+		names = append(names, strings.TrimSpace(cfg.Name))
+	}
+	for _, cfg := range s.RSSSources {
+		names = append(names, strings.TrimSpace(cfg.Name))
+	}
+	for _, cfg := range s.ForumThreads {
+		names = append(names, strings.TrimSpace(cfg.Name))
+	}
+	return names
+}
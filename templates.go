@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// defaultPushBodyTemplate 与原先固定的推送正文保持一致。
+// 有正文摘要时在标题下多一行摘要，没有时输出与原先完全相同。
+const defaultPushBodyTemplate = "{{if .Title}}{{.Title}}{{else}}有新消息{{end}}{{if .Summary}}\n{{.Summary}}{{end}}\n来源：天龙怀旧公告检测\n链接：{{if .Link}}{{.Link}}{{else}}无{{end}}"
+
+// PushTemplate 为某个来源的推送标题与正文模板（text/template 语法）。
+// 可用占位符：{{.Source}} {{.Title}} {{.Link}} {{.Time}} {{.Keywords}} {{.Summary}}，
+// 其中 .Keywords 为命中的关键词列表，可写成 {{join .Keywords "、"}}；.Summary 为公告正文摘要，可能为空。
+// 正文模板只用于发送纯文本的渠道（息知、Server酱、PushPlus、Bark、Webhook 与邮件的纯文本部分）；
+// 群机器人、Telegram 与邮件的 HTML 部分自行按标题、链接与摘要排版，只使用标题模板。
+type PushTemplate struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// pushTemplateData 为渲染推送模板时的数据。
+type pushTemplateData struct {
+	Source   string
+	Title    string
+	Link     string
+	Time     string
+	Keywords []string
+	Summary  string
+}
+
+var pushTemplateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// defaultPushTemplate 返回来源的默认模板：标题为原先的推送标题，正文为原先的固定格式。
+func defaultPushTemplate(c pushSource) PushTemplate {
+	return PushTemplate{Title: c.PushHead(), Body: defaultPushBodyTemplate}
+}
+
+// defaultPushTemplates 返回内置来源与推送类别的默认模板，按来源名称索引。
+func defaultPushTemplates() map[string]PushTemplate {
+	out := map[string]PushTemplate{}
+	for _, c := range builtinCheckers() {
+		out[c.Name()] = defaultPushTemplate(c)
+	}
+	for _, c := range pushCategories() {
+		out[c.Name()] = defaultPushTemplate(c)
+	}
+	return out
+}
+
+// copyPushTemplates 复制模板表并丢弃标题与正文都为空的条目。
+func copyPushTemplates(in map[string]PushTemplate) map[string]PushTemplate {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make(map[string]PushTemplate, len(in))
+	for source, t := range in {
+		source = strings.TrimSpace(source)
+		if source == "" || (strings.TrimSpace(t.Title) == "" && strings.TrimSpace(t.Body) == "") {
+			continue
+		}
+		out[source] = t
+	}
+	return out
+}
+
+// withDefaults 用默认值补齐未填写的标题或正文。
+func (t PushTemplate) withDefaults(def PushTemplate) PushTemplate {
+	if strings.TrimSpace(t.Title) == "" {
+		t.Title = def.Title
+	}
+	if strings.TrimSpace(t.Body) == "" {
+		t.Body = def.Body
+	}
+	return t
+}
+
+func newPushTemplateData(source string, title string, link string, keywords []string, at time.Time) pushTemplateData {
+	return pushTemplateData{
+		Source:   strings.TrimSpace(source),
+		Title:    strings.TrimSpace(title),
+		Link:     strings.TrimSpace(link),
+		Time:     at.Format("2006-01-02 15:04:05"),
+		Keywords: keywords,
+	}
+}
+
+// plainPushBody 不经模板直接拼出与默认正文模板相同的内容，用于模板全部渲染失败时。
+func plainPushBody(data pushTemplateData) string {
+	title := data.Title
+	if title == "" {
+		title = "有新消息"
+	}
+	link := data.Link
+	if link == "" {
+		link = "无"
+	}
+	body := title
+	if data.Summary != "" {
+		body += "\n" + data.Summary
+	}
+	return body + "\n来源：天龙怀旧公告检测\n链接：" + link
+}
+
+func executePushTemplate(name string, text string, data pushTemplateData) (string, error) {
+	tmpl, err := template.New(name).Funcs(pushTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// render 渲染推送标题与正文。
+func (t PushTemplate) render(data pushTemplateData) (head string, body string, err error) {
+	head, err = executePushTemplate("title", t.Title, data)
+	if err != nil {
+		return "", "", errors.New("标题模板: " + err.Error())
+	}
+	body, err = executePushTemplate("body", t.Body, data)
+	if err != nil {
+		return "", "", errors.New("正文模板: " + err.Error())
+	}
+	return strings.TrimSpace(head), body, nil
+}
+
+// validatePushHeads 校验论坛版块与自定义来源填写的推送标题：它们是来源的默认标题模板，
+// 需要能解析，并能用示例数据渲染。
+func validatePushHeads(s AppSettings) error {
+	var checks []checker
+	for _, b := range s.ForumBoards {
+		checks = append(checks, forumChecker{board: b})
+	}
+	for _, cfg := range s.HTMLSources {
+		checks = append(checks, htmlSourceChecker{cfg: cfg})
+	}
+	for _, cfg := range s.JSONSources {
+		checks = append(checks, jsonSourceChecker{cfg: cfg})
+	}
+	for _, cfg := range s.RSSSources {
+		checks = append(checks, rssSourceChecker{cfg: cfg})
+	}
+	for _, cfg := range s.ForumThreads {
+		checks = append(checks, threadChecker{cfg: cfg})
+	}
+
+	sample := newPushTemplateData("", "示例标题", "http://tlhj.changyou.com/", []string{"维护"}, time.Now())
+	for _, c := range checks {
+		sample.Source = c.Name()
+		if _, err := executePushTemplate("title", c.PushHead(), sample); err != nil {
+			return errors.New(c.Name() + ": 推送标题有误: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// validatePushTemplates 在保存设置时校验模板：能解析，并能用示例数据渲染出非空标题。
+func validatePushTemplates(templates map[string]PushTemplate) error {
+	defaults := defaultPushTemplates()
+	sample := newPushTemplateData("公告", "示例标题", "http://tlhj.changyou.com/", []string{"维护"}, time.Now())
+	for source, t := range templates {
+		def, ok := defaults[source]
+		if !ok {
+			def = PushTemplate{Title: "消息通知", Body: defaultPushBodyTemplate}
+		}
+		sample.Source = source
+		head, _, err := t.withDefaults(def).render(sample)
+		if err != nil {
+			return errors.New(source + "推送模板有误: " + err.Error())
+		}
+		if head == "" {
+			return errors.New(source + "推送模板有误: 标题渲染结果为空")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlainPushBodyMatchesDefaultTemplate(t *testing.T) {
+	at := time.Date(2026, 10, 16, 20, 0, 0, 0, beijingTime)
+	for _, data := range []pushTemplateData{
+		newPushTemplateData("公告", "维护公告", "http://tlhj.changyou.com/a", nil, at),
+		newPushTemplateData("公告", "", "", nil, at),
+		{Source: "公告", Title: "维护公告", Summary: "今日 8:00 停服维护"},
+	} {
+		want, err := executePushTemplate("body", defaultPushBodyTemplate, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := plainPushBody(data); got != want {
+			t.Errorf("plainPushBody = %q, 期望 %q", got, want)
+		}
+	}
+}
+
+func TestNewPushMessageWithBrokenPushHead(t *testing.T) {
+	c := htmlSourceChecker{cfg: HTMLSourceConfig{Name: "新闻", PushHead: "新闻{{更新"}}
+	item := latestItem{Key: "1", Title: "版本更新", Link: "http://example.com/1"}
+	msg, err := newPushMessage(c, item, time.Now(), PushTemplate{}, nil)
+	if err == nil {
+		t.Fatal("推送标题有误时应返回错误")
+	}
+	if !strings.HasPrefix(msg.Body, "版本更新\n") || !strings.Contains(msg.Body, item.Link) {
+		t.Fatalf("正文 = %q", msg.Body)
+	}
+	if msg.Head == "" {
+		t.Fatal("标题为空")
+	}
+}
+
+func TestValidatePushHeads(t *testing.T) {
+	ok := AppSettings{HTMLSources: []HTMLSourceConfig{{Name: "新闻", PushHead: "新闻：{{.Title}}"}}}
+	if err := validatePushHeads(ok); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []AppSettings{
+		{HTMLSources: []HTMLSourceConfig{{Name: "新闻", PushHead: "新闻{{更新"}}},
+		{RSSSources: []RSSSourceConfig{{Name: "博客", PushHead: "{{.Titel}}"}}},
+		{ForumBoards: []ForumBoardConfig{{FID: 2, Name: "论坛", PushHead: "{{"}}},
+	} {
+		if err := validatePushHeads(s); err == nil {
+			t.Errorf("%+v 应校验失败", s)
+		}
+	}
+}
+
+func TestPushCategoriesHaveTemplatesAndReserveNames(t *testing.T) {
+	defaults := defaultPushTemplates()
+	for _, c := range pushCategories() {
+		if _, ok := c.(checker); ok {
+			t.Errorf("%s 只用于推送，不应实现 checker", c.Name())
+		}
+		if defaults[c.Name()].Title != c.PushHead() {
+			t.Errorf("%s 缺少默认模板", c.Name())
+		}
+		if err := validateSourceNames([]string{c.Name()}); err == nil {
+			t.Errorf("自定义来源不应与 %s 重名", c.Name())
+		}
+	}
+}
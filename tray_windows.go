@@ -4,6 +4,7 @@ package main
 
 import (
 	_ "embed"
+	"strconv"
 	"sync"
 
 	"github.com/getlantern/systray"
@@ -27,6 +28,10 @@ func setupTray(app *App) {
 			systray.SetTooltip(AppName)
 
 			showItem := systray.AddMenuItem("显示", "显示主窗口")
+			burstItem := systray.AddMenuItem(
+				"加速检查 "+strconv.Itoa(defaultBurstMinutes)+" 分钟",
+				"每 "+strconv.Itoa(defaultBurstIntervalSec)+" 秒检查一次所有来源",
+			)
 			systray.AddSeparator()
 			quitItem := systray.AddMenuItem("退出", "退出程序")
 
@@ -38,6 +43,12 @@ func setupTray(app *App) {
 							runtime.WindowShow(app.ctx)
 							runtime.WindowUnminimise(app.ctx)
 						}
+					case <-burstItem.ClickedCh:
+						if app != nil && app.monitor != nil {
+							if err := app.monitor.StartBurst(defaultBurstIntervalSec, defaultBurstMinutes); err != nil {
+								app.monitor.emitLog(app.ctx, "WARN", "加速检查未开启: "+err.Error())
+							}
+						}
 					case <-quitItem.ClickedCh:
 						if app != nil {
 							app.allowQuit.Store(true)